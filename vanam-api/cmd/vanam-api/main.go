@@ -2,12 +2,22 @@ package main
 
 import (
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prabalesh/vanam/vanam-api/internal/config"
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
 	"github.com/prabalesh/vanam/vanam-api/internal/handlers"
+	"github.com/prabalesh/vanam/vanam-api/internal/jobs"
+	"github.com/prabalesh/vanam/vanam-api/internal/mailer"
+	"github.com/prabalesh/vanam/vanam-api/internal/media"
 	"github.com/prabalesh/vanam/vanam-api/internal/middleware"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/oidc"
+	"github.com/prabalesh/vanam/vanam-api/internal/payments"
+	"github.com/prabalesh/vanam/vanam-api/internal/storage"
+	"github.com/prabalesh/vanam/vanam-api/internal/tokens"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
 	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
 )
 
@@ -24,11 +34,42 @@ func main() {
 	database.SeedData()
 	redis.Connect(cfg)
 
+	payments.Setup(cfg)
+	mailer.Setup(cfg)
+	storage.Setup(cfg)
+	media.Init(cfg.MediaSigningKey, cfg.MediaBaseURL, time.Duration(cfg.MediaURLTTLHours)*time.Hour)
+	tokens.Init(cfg.TokenSecret)
+	oidc.Setup(cfg)
+
+	if err := utils.InitTicketSigner(cfg.TicketSigningKey); err != nil {
+		log.Fatal("Failed to load ticket signing key:", err)
+	}
+
+	// background jobs
+	jobs.Every("expire-rebook-offers", 15*time.Minute, handlers.ExpireRebookOffers)
+	jobs.Every("deliver-notifications", time.Minute, handlers.DeliverNotifications)
+	jobs.Every("update-movie-statuses", 10*time.Minute, handlers.UpdateMovieStatuses)
+
 	r := gin.Default()
 
 	// middlewares
 	r.Use(middleware.CORS())
 
+	// rate limit policies
+	catalogLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "catalog", Limit: cfg.RateLimitCatalogPerMinute, Window: time.Minute, KeyBy: middleware.ByClient,
+	})
+	authLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "auth", Limit: cfg.RateLimitAuthPerMinute, Window: time.Minute, KeyBy: middleware.ByIP,
+	})
+	userLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "user", Limit: cfg.RateLimitUserPerMinute, Window: time.Minute, KeyBy: middleware.ByClient,
+	})
+	// Runs before authentication so requests with bad credentials are throttled too
+	ipLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "ip", Limit: cfg.RateLimitIPPerMinute, Window: time.Minute, KeyBy: middleware.ByIP,
+	})
+
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status":  "ok",
@@ -39,9 +80,11 @@ func main() {
 
 	// Public API routes
 	public := r.Group("/api/v1")
+	public.Use(middleware.Localization())
 	{
 		// Theater routes (public)
 		theaterPublic := public.Group("/theaters")
+		theaterPublic.Use(middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit)
 		{
 			theaterPublic.GET("", handlers.GetTheaters)                     // GET /api/v1/theaters
 			theaterPublic.GET("/:id", handlers.GetTheaterByID)              // GET /api/v1/theaters/:id
@@ -50,29 +93,79 @@ func main() {
 
 		// Screen routes (public)
 		screenPublic := public.Group("/screens")
+		screenPublic.Use(middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit)
 		{
 			screenPublic.GET("", handlers.GetAllScreens)     // GET /api/v1/screens
 			screenPublic.GET("/:id", handlers.GetScreenByID) // GET /api/v1/screens/:id
 		}
 
 		// Language routes
-		public.GET("/languages", handlers.GetLanguages)
+		public.GET("/languages", middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit, handlers.GetLanguages)
 
 		// Genre routes
-		public.GET("/genres", handlers.GetAllGenres)
+		public.GET("/genres", middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit, handlers.GetAllGenres)
+
+		// Rating routes
+		public.GET("/ratings", middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit, handlers.GetRatings)
+		public.GET("/certifications", middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit, handlers.GetCertificationSystems) // ?country=IN
 
 		// Movie routes
 		moviePublic := public.Group("/movies")
+		moviePublic.Use(middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit)
 		{
 			moviePublic.GET("", handlers.GetAllMovies)
+			moviePublic.GET("/now-showing", handlers.GetNowShowingMovies)
+			moviePublic.GET("/coming-soon", handlers.GetComingSoonMovies)
 			moviePublic.GET("/:id", handlers.GetMovieByID)
+			moviePublic.GET("/:id/subtitles/:lang", handlers.GetSubtitles) // ?format=srt|vtt
+			moviePublic.GET("/:id/assets", handlers.GetMediaAssets)        // ?kind=poster|backdrop|still|trailer
+			moviePublic.GET("/:id/credits", handlers.GetMovieCredits)
+		}
+
+		// Media files, through signed URLs handed out with movies and assets
+		public.GET("/media/*key", handlers.ServeMedia) // ?expires=...&sig=...
+
+		// Search routes
+		searchPublic := public.Group("/search")
+		searchPublic.Use(middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit)
+		{
+			searchPublic.GET("", handlers.Search)                          // GET /api/v1/search?q=jailer&types=movie,person
+			searchPublic.GET("/autocomplete", handlers.SearchAutocomplete) // GET /api/v1/search/autocomplete?q=jai
 		}
 
 		// Screening routes
 		screeningPublic := public.Group("/screenings")
+		screeningPublic.Use(middleware.OptionalAPIKey(models.ScopeCatalogRead), catalogLimit)
 		{
 			screeningPublic.GET("", handlers.GetScreenings)
 			screeningPublic.GET("/:id", handlers.GetScreeningByID)
+			screeningPublic.GET("/:id/seats", handlers.GetScreeningSeatMap)
+			screeningPublic.GET("/:id/seats/suggest", handlers.SuggestSeats)
+		}
+
+		// Booking routes (logged-in users and partner API keys)
+		bookingPublic := public.Group("/bookings")
+		bookingPublic.Use(ipLimit, middleware.UserAuthMiddleware(models.ScopeBookingsRead), userLimit)
+		{
+			bookingWrite := middleware.RequireScope(models.ScopeBookingsWrite)
+
+			bookingPublic.POST("", bookingWrite, handlers.CreateBooking)
+			bookingPublic.GET("", handlers.GetMyBookings)
+			bookingPublic.GET("/:id", handlers.GetBookingByID)
+			bookingPublic.GET("/:id/cancellation", handlers.GetCancellationQuote)
+			bookingPublic.POST("/:id/cancel", bookingWrite, handlers.CancelBooking)
+			bookingPublic.GET("/:id/rebook", handlers.GetRebookOptions)
+			bookingPublic.POST("/:id/rebook", bookingWrite, handlers.AcceptRebookOffer)
+			bookingPublic.POST("/:id/rebook/decline", bookingWrite, handlers.DeclineRebookOffer)
+		}
+
+		// Session routes (logged-in users)
+		sessionPublic := public.Group("/sessions")
+		sessionPublic.Use(ipLimit, middleware.UserAuthMiddleware(), userLimit)
+		{
+			sessionPublic.GET("", handlers.GetMySessions)
+			sessionPublic.DELETE("", handlers.RevokeMyOtherSessions)
+			sessionPublic.DELETE("/:session_id", handlers.RevokeMySession)
 		}
 	}
 
@@ -81,17 +174,48 @@ func main() {
 	{
 		// Admin authentication
 		auth := adminAPI.Group("/auth")
+		auth.Use(authLimit)
 		{
 			auth.POST("/login", handlers.AdminLogin)
+			auth.POST("/2fa/verify", handlers.VerifyTwoFactorLogin)
+			auth.POST("/password-reset", handlers.RequestPasswordReset)
+			auth.POST("/password-reset/confirm", handlers.ConfirmPasswordReset)
+			auth.POST("/verify-email", handlers.VerifyEmail)
+			auth.GET("/password-policy", handlers.GetPasswordPolicy)
+			auth.GET("/oidc/login", handlers.OIDCLogin)
+			auth.GET("/oidc/callback", handlers.OIDCCallback)
+		}
+
+		// Entry check-in, also open to kiosk API keys
+		checkinGroup := adminAPI.Group("/checkin")
+		checkinGroup.Use(ipLimit, middleware.UserAuthMiddleware(models.ScopeCheckin), userLimit)
+		{
+			checkinGroup.POST("/scan", handlers.ScanTicket)
+			checkinGroup.GET("/public-key", handlers.GetTicketPublicKey)
+			checkinGroup.GET("/screenings/:id/attendance", handlers.GetScreeningAttendance)
 		}
 
 		// All admin routes require authentication and admin role
 		adminProtected := adminAPI.Group("/")
-		adminProtected.Use(middleware.UserAuthMiddleware())
+		adminProtected.Use(ipLimit, middleware.UserAuthMiddleware(), userLimit)
 		{
 			// Profile and logout
 			adminProtected.GET("/profile", handlers.GetUserDetails)
+			adminProtected.PUT("/profile/preferences", handlers.UpdatePreferences)
+			adminProtected.PUT("/profile/date-of-birth", handlers.UpdateDateOfBirth)
 			adminProtected.POST("/logout", handlers.AdminLogout)
+			adminProtected.POST("/change-password", handlers.ChangePassword)
+			adminProtected.POST("/verify-email/resend", handlers.ResendVerificationEmail)
+
+			// Two-factor authentication
+			twoFactorGroup := adminProtected.Group("/2fa")
+			{
+				twoFactorGroup.GET("", handlers.GetTwoFactorStatus)
+				twoFactorGroup.POST("/enroll", handlers.EnrollTwoFactor)
+				twoFactorGroup.POST("/enable", handlers.EnableTwoFactor)
+				twoFactorGroup.POST("/disable", handlers.DisableTwoFactor)
+				twoFactorGroup.POST("/recovery-codes", handlers.RegenerateRecoveryCodes)
+			}
 
 			// Role management
 			adminRolesProtected := adminProtected.Group("/roles")
@@ -111,14 +235,59 @@ func main() {
 				adminUsersProtected.POST("/", handlers.CreateUser)
 				adminUsersProtected.PUT("/:id", handlers.UpdateUser)
 				adminUsersProtected.DELETE("/:id", handlers.DeleteUser)
+				adminUsersProtected.GET("/:id/sessions", handlers.GetUserSessions)
+				adminUsersProtected.DELETE("/:id/sessions/:session_id", handlers.RevokeUserSession)
+				adminUsersProtected.POST("/:id/logout", handlers.ForceLogoutUser)
+				adminUsersProtected.POST("/:id/unlock", handlers.UnlockUser)
+				adminUsersProtected.DELETE("/:id/2fa", handlers.ResetUserTwoFactor)
+				adminUsersProtected.POST("/:id/impersonate", handlers.ImpersonateUser)
+				adminUsersProtected.POST("/:id/verify-age", handlers.VerifyUserAge)
+				adminUsersProtected.DELETE("/:id/verify-age", handlers.ClearAgeVerification)
 			}
 
+			// Authentication audit log
+			adminProtected.GET("/security-events", handlers.GetSecurityEvents)
+
 			// Genre management
 			genreGroup := adminProtected.Group("/genres")
 			{
 				genreGroup.POST("", handlers.CreateGenre)
 				genreGroup.PUT("/:id", handlers.UpdateGenre)
 				genreGroup.DELETE("/:id", handlers.DeleteGenre)
+				genreGroup.GET("/:id/languages", handlers.GetGenreLanguages)
+				genreGroup.POST("/:id/languages", handlers.AddGenreLanguage)
+				genreGroup.PUT("/:id/languages/:langId", handlers.UpdateGenreLanguage)
+				genreGroup.DELETE("/:id/languages/:langId", handlers.RemoveGenreLanguage)
+			}
+
+			// Translation files for movie and genre localizations
+			translationGroup := adminProtected.Group("/translations")
+			{
+				translationGroup.GET("/export", handlers.ExportTranslations)  // ?lang=ta&format=csv|xliff&missing_only=true
+				translationGroup.POST("/import", handlers.ImportTranslations) // multipart file, ?dry_run=true&force=true
+			}
+
+			// Rating descriptions
+			ratingGroup := adminProtected.Group("/rating-descriptions")
+			{
+				ratingGroup.GET("", handlers.GetRatingDescriptions)
+				ratingGroup.POST("", handlers.CreateRatingDescription)
+				ratingGroup.PUT("/:id", handlers.UpdateRatingDescription)
+				ratingGroup.DELETE("/:id", handlers.DeleteRatingDescription)
+			}
+
+			// Regional certification systems
+			certificationSystemGroup := adminProtected.Group("/certification-systems")
+			{
+				certificationSystemGroup.GET("", handlers.GetCertificationSystems) // ?all=true includes inactive systems
+				certificationSystemGroup.POST("", handlers.CreateCertificationSystem)
+				certificationSystemGroup.PUT("/:id", handlers.UpdateCertificationSystem)
+				certificationSystemGroup.POST("/:id/certifications", handlers.CreateCertification)
+			}
+			certificationGroup := adminProtected.Group("/certifications")
+			{
+				certificationGroup.PUT("/:id", handlers.UpdateCertification)
+				certificationGroup.DELETE("/:id", handlers.DeleteCertification)
 			}
 
 			// Language management
@@ -133,13 +302,38 @@ func main() {
 			adminMoviesProtected := adminProtected.Group("/movies")
 			{
 				adminMoviesProtected.POST("", handlers.CreateMovie)
+				adminMoviesProtected.POST("/import", handlers.ImportCatalog) // multipart CSV or JSON file, ?dry_run=true&skip_invalid=true
 				adminMoviesProtected.PUT("/:id", handlers.UpdateMovie)
 				adminMoviesProtected.DELETE("/:id", handlers.DeleteMovie)
 				adminMoviesProtected.GET("/:id/languages", handlers.GetMovieLanguages)
 				adminMoviesProtected.POST("/:id/languages", handlers.AddMovieLanguage)
 				adminMoviesProtected.PUT("/:id/languages/:langId", handlers.UpdateMovieLanguage)
 				adminMoviesProtected.DELETE("/:id/languages/:langId", handlers.RemoveMovieLanguage)
-				adminMoviesProtected.GET("/:id", handlers.GetMovieByID) // Supports ?lang=hi parameter
+				adminMoviesProtected.POST("/:id/languages/:langId/subtitles", handlers.UploadSubtitles) // multipart file, ?dry_run=true
+				adminMoviesProtected.DELETE("/:id/languages/:langId/subtitles", handlers.DeleteSubtitles)
+				adminMoviesProtected.GET("/:id/assets", handlers.GetMediaAssets)
+				adminMoviesProtected.POST("/:id/assets", handlers.UploadMediaAsset) // multipart file, kind=poster|backdrop|still
+				adminMoviesProtected.POST("/:id/trailers", handlers.AddTrailer)
+				adminMoviesProtected.PATCH("/:id/assets/:assetId", handlers.UpdateMediaAsset)
+				adminMoviesProtected.DELETE("/:id/assets/:assetId", handlers.DeleteMediaAsset)
+				adminMoviesProtected.GET("/:id/certifications", handlers.GetMovieCertifications)
+				adminMoviesProtected.PUT("/:id/certifications", handlers.SetMovieCertification) // One per certification system
+				adminMoviesProtected.DELETE("/:id/certifications/:certificationId", handlers.RemoveMovieCertification)
+				adminMoviesProtected.POST("/:id/advisories", handlers.AddContentAdvisory)
+				adminMoviesProtected.DELETE("/:id/advisories/:advisoryId", handlers.RemoveContentAdvisory)
+				adminMoviesProtected.GET("/:id/credits", handlers.GetMovieCredits)
+				adminMoviesProtected.PUT("/:id/credits", handlers.SetMovieCredits) // Replaces cast and crew
+				adminMoviesProtected.GET("/:id", handlers.GetMovieByID)            // Supports ?lang=hi parameter
+			}
+
+			// Duplicate movies and people, and merges that can be undone
+			adminProtected.GET("/duplicates/movies", handlers.FindDuplicateMovies) // ?min_score=0.5&limit=50
+			adminProtected.GET("/duplicates/people", handlers.FindDuplicatePeople)
+			mergeGroup := adminProtected.Group("/merges")
+			{
+				mergeGroup.GET("", handlers.GetMerges) // ?kind=movie|person&record_id=
+				mergeGroup.POST("", handlers.MergeDuplicate)
+				mergeGroup.POST("/:id/undo", handlers.UndoMerge)
 			}
 
 			// Theater management (admin)
@@ -170,6 +364,33 @@ func main() {
 				adminScreeningsProtected.POST("", handlers.CreateScreening)
 				adminScreeningsProtected.PUT("/:id", handlers.UpdateScreening)
 				adminScreeningsProtected.DELETE("/:id", handlers.DeleteScreening)
+				adminScreeningsProtected.POST("/:id/cancel", handlers.CancelScreening)
+				adminScreeningsProtected.GET("/:id/cancellation", handlers.GetScreeningCancellation)
+			}
+
+			// Booking management
+			bookingAdmin := adminProtected.Group("/bookings")
+			{
+				bookingAdmin.POST("/:id/cancel", handlers.AdminCancelBooking)
+			}
+
+			// Cancellation policies
+			cancellationPolicyGroup := adminProtected.Group("/cancellation-policies")
+			{
+				cancellationPolicyGroup.GET("", handlers.GetCancellationPolicies)
+				cancellationPolicyGroup.POST("", handlers.CreateCancellationPolicy)
+				cancellationPolicyGroup.PUT("/:id", handlers.UpdateCancellationPolicy)
+				cancellationPolicyGroup.DELETE("/:id", handlers.DeleteCancellationPolicy)
+			}
+
+			// API keys for partners and kiosks
+			apiKeyGroup := adminProtected.Group("/api-keys")
+			{
+				apiKeyGroup.GET("", handlers.GetAPIKeys)
+				apiKeyGroup.POST("", handlers.CreateAPIKey)
+				apiKeyGroup.GET("/:id", handlers.GetAPIKeyByID)
+				apiKeyGroup.PUT("/:id", handlers.UpdateAPIKey)
+				apiKeyGroup.DELETE("/:id", handlers.RevokeAPIKey)
 			}
 
 			// Dashboard
@@ -0,0 +1,157 @@
+// Package catalog reads bulk movie imports. A bundle is a list of movies, each with an
+// external ID from the distributor or upstream catalog so that importing the same
+// bundle again updates the movies instead of duplicating them.
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry is one movie in an import bundle. Genres and cast are given by name. A nil Cast
+// leaves a movie's cast as it is and an empty one clears it.
+type Entry struct {
+	ExternalID      string         `json:"external_id"`
+	OriginalTitle   string         `json:"original_title"`
+	DurationMinutes int            `json:"duration_minutes"`
+	ReleaseDate     string         `json:"release_date"` // 2025-01-31 or RFC 3339
+	Rating          string         `json:"rating"`
+	Description     string         `json:"description"`
+	PosterURL       string         `json:"poster_url"`
+	ComingSoonAt    string         `json:"coming_soon_at"`
+	BookingOpensAt  string         `json:"booking_opens_at"`
+	Genres          []string       `json:"genres"`
+	Cast            []string       `json:"cast"`
+	Localizations   []Localization `json:"localizations"`
+
+	Row int `json:"-"` // Line of a CSV row, position in a JSON list
+}
+
+// Localization is a movie's title and description in one language
+type Localization struct {
+	Language    string `json:"language"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Issue is a problem with one row of a bundle
+type Issue struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ParseDate reads the dates used in bundles
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// Validate checks an entry on its own, without looking anything up. ratings are the
+// accepted certification codes.
+func Validate(entry Entry, ratings []string) []string {
+	var problems []string
+
+	if strings.TrimSpace(entry.ExternalID) == "" {
+		problems = append(problems, "external_id is required")
+	} else if len(entry.ExternalID) > 100 {
+		problems = append(problems, "external_id must be at most 100 characters")
+	}
+	if strings.TrimSpace(entry.OriginalTitle) == "" {
+		problems = append(problems, "original_title is required")
+	} else if len(entry.OriginalTitle) > 255 {
+		problems = append(problems, "original_title must be at most 255 characters")
+	}
+	if entry.DurationMinutes < 1 {
+		problems = append(problems, "duration_minutes must be at least 1")
+	}
+	if len(entry.PosterURL) > 500 {
+		problems = append(problems, "poster_url must be at most 500 characters")
+	}
+
+	if entry.ReleaseDate == "" {
+		problems = append(problems, "release_date is required")
+	} else if _, err := ParseDate(entry.ReleaseDate); err != nil {
+		problems = append(problems, "release_date: "+err.Error())
+	}
+	if entry.ComingSoonAt != "" {
+		if _, err := ParseDate(entry.ComingSoonAt); err != nil {
+			problems = append(problems, "coming_soon_at: "+err.Error())
+		}
+	}
+	if entry.BookingOpensAt != "" {
+		if _, err := ParseDate(entry.BookingOpensAt); err != nil {
+			problems = append(problems, "booking_opens_at: "+err.Error())
+		}
+	}
+
+	validRating := false
+	for _, rating := range ratings {
+		validRating = validRating || entry.Rating == rating
+	}
+	if !validRating {
+		problems = append(problems, fmt.Sprintf("rating must be one of %s", strings.Join(ratings, ", ")))
+	}
+
+	if len(entry.Genres) == 0 {
+		problems = append(problems, "at least one genre is required")
+	}
+	for _, name := range entry.Cast {
+		if strings.TrimSpace(name) == "" {
+			problems = append(problems, "cast names must not be empty")
+			break
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, localization := range entry.Localizations {
+		code := strings.ToLower(strings.TrimSpace(localization.Language))
+		switch {
+		case code == "":
+			problems = append(problems, "localizations need a language")
+		case seen[code]:
+			problems = append(problems, fmt.Sprintf("language %q is localized twice", localization.Language))
+		case strings.TrimSpace(localization.Title) == "":
+			problems = append(problems, fmt.Sprintf("localized title for %q is required", localization.Language))
+		}
+		seen[code] = true
+	}
+
+	return problems
+}
+
+// DuplicateExternalIDs reports every row that reuses the external ID of an earlier row
+func DuplicateExternalIDs(entries []Entry) []Issue {
+	var issues []Issue
+	firstRow := make(map[string]int)
+	for _, entry := range entries {
+		id := strings.TrimSpace(entry.ExternalID)
+		if id == "" {
+			continue
+		}
+		if row, ok := firstRow[id]; ok {
+			issues = append(issues, Issue{Row: entry.Row, Message: fmt.Sprintf("external_id is already used by row %d", row)})
+			continue
+		}
+		firstRow[id] = entry.Row
+	}
+	return issues
+}
+
+// splitList reads a "|" separated CSV cell
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
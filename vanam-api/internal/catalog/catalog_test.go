@@ -0,0 +1,286 @@
+package catalog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Entry
+		issues  []Issue
+		wantErr string
+	}{
+		{
+			name: "lists and localizations",
+			input: "external_id,original_title,duration_minutes,release_date,rating,genres,cast,title:ta,description:ta\n" +
+				"m1,Leo,164,2023-10-19,U/A,Action| Thriller ,Vijay|Trisha,லியோ,\n",
+			want: []Entry{{
+				ExternalID:      "m1",
+				OriginalTitle:   "Leo",
+				DurationMinutes: 164,
+				ReleaseDate:     "2023-10-19",
+				Rating:          "U/A",
+				Genres:          []string{"Action", "Thriller"},
+				Cast:            []string{"Vijay", "Trisha"},
+				Localizations:   []Localization{{Language: "ta", Title: "லியோ"}},
+				Row:             2,
+			}},
+		},
+		{
+			name:  "byte order mark before the header",
+			input: "\ufeffexternal_id,original_title\nm1,Leo\n",
+			want:  []Entry{{ExternalID: "m1", OriginalTitle: "Leo", Row: 2}},
+		},
+		{
+			name:  "quoted newline in a cell",
+			input: "external_id,original_title,description\nm1,Leo,\"First line\nsecond line\"\nm2,Jailer,\n",
+			want: []Entry{
+				{ExternalID: "m1", OriginalTitle: "Leo", Description: "First line\nsecond line", Row: 2},
+				{ExternalID: "m2", OriginalTitle: "Jailer", Row: 3},
+			},
+		},
+		{
+			name:  "empty cast cell leaves the cast",
+			input: "external_id,original_title,cast\nm1,Leo,\n",
+			want:  []Entry{{ExternalID: "m1", OriginalTitle: "Leo", Row: 2}},
+		},
+		{
+			name:   "duration that is not a number",
+			input:  "external_id,original_title,duration_minutes\nm1,Leo,long\nm2,Jailer,168\n",
+			want:   []Entry{{ExternalID: "m2", OriginalTitle: "Jailer", DurationMinutes: 168, Row: 3}},
+			issues: []Issue{{Row: 2, Message: `duration_minutes "long" is not a number`}},
+		},
+		{
+			name:    "missing required column",
+			input:   "external_id,title\nm1,Leo\n",
+			wantErr: `missing the "original_title" column`,
+		},
+		{
+			name:    "unterminated quote",
+			input:   "external_id,original_title\nm1,\"Leo\n",
+			wantErr: "failed to read CSV",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, issues, err := ReadCSV(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("entries = %+v, want %+v", entries, tt.want)
+			}
+			if !reflect.DeepEqual(issues, tt.issues) {
+				t.Errorf("issues = %+v, want %+v", issues, tt.issues)
+			}
+		})
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Entry
+		wantErr bool
+	}{
+		{
+			name:  "list of entries",
+			input: `[{"external_id": "m1", "original_title": "Leo"}, {"external_id": "m2", "original_title": "Jailer"}]`,
+			want: []Entry{
+				{ExternalID: "m1", OriginalTitle: "Leo", Row: 1},
+				{ExternalID: "m2", OriginalTitle: "Jailer", Row: 2},
+			},
+		},
+		{
+			name:  "movies object",
+			input: ` {"movies": [{"external_id": "m1", "genres": ["Action"]}]}`,
+			want:  []Entry{{ExternalID: "m1", Genres: []string{"Action"}, Row: 1}},
+		},
+		{
+			name:  "byte order mark",
+			input: "\ufeff" + `[{"external_id": "m1"}]`,
+			want:  []Entry{{ExternalID: "m1", Row: 1}},
+		},
+		{
+			name:  "empty cast is kept apart from a missing one",
+			input: `[{"external_id": "m1", "cast": []}, {"external_id": "m2"}]`,
+			want: []Entry{
+				{ExternalID: "m1", Cast: []string{}, Row: 1},
+				{ExternalID: "m2", Row: 2},
+			},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `[{"external_id": "m1",}]`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			input:   `[{"duration_minutes": "long"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadJSON(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", entries)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("entries = %+v, want %+v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ratings := []string{"U", "U/A", "U/A 13+", "A"}
+	valid := Entry{
+		ExternalID:      "m1",
+		OriginalTitle:   "Leo",
+		DurationMinutes: 164,
+		ReleaseDate:     "2023-10-19",
+		Rating:          "U/A 13+",
+		Genres:          []string{"Action"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(entry *Entry)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			modify: func(entry *Entry) {},
+		},
+		{
+			name:   "RFC 3339 dates",
+			modify: func(entry *Entry) { entry.ReleaseDate = "2023-10-19T09:00:00+05:30" },
+		},
+		{
+			name: "missing required fields",
+			modify: func(entry *Entry) {
+				entry.ExternalID = " "
+				entry.OriginalTitle = ""
+				entry.DurationMinutes = 0
+				entry.ReleaseDate = ""
+				entry.Genres = nil
+			},
+			want: []string{
+				"external_id is required",
+				"original_title is required",
+				"duration_minutes must be at least 1",
+				"release_date is required",
+				"at least one genre is required",
+			},
+		},
+		{
+			name: "bad dates",
+			modify: func(entry *Entry) {
+				entry.ReleaseDate = "19/10/2023"
+				entry.BookingOpensAt = "2023-13-01"
+			},
+			want: []string{
+				`release_date: invalid date "19/10/2023", use YYYY-MM-DD`,
+				`booking_opens_at: invalid date "2023-13-01", use YYYY-MM-DD`,
+			},
+		},
+		{
+			name:   "unknown rating",
+			modify: func(entry *Entry) { entry.Rating = "PG-13" },
+			want:   []string{"rating must be one of U, U/A, U/A 13+, A"},
+		},
+		{
+			name:   "blank cast name",
+			modify: func(entry *Entry) { entry.Cast = []string{"Vijay", " ", ""} },
+			want:   []string{"cast names must not be empty"},
+		},
+		{
+			name:   "empty cast",
+			modify: func(entry *Entry) { entry.Cast = []string{} },
+		},
+		{
+			name: "localizations",
+			modify: func(entry *Entry) {
+				entry.Localizations = []Localization{
+					{Language: "ta", Title: "லியோ"},
+					{Language: "TA", Title: "Leo"},
+					{Language: "hi"},
+					{Title: "Leo"},
+				}
+			},
+			want: []string{
+				`language "TA" is localized twice`,
+				`localized title for "hi" is required`,
+				"localizations need a language",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := valid
+			tt.modify(&entry)
+			if problems := Validate(entry, ratings); !reflect.DeepEqual(problems, tt.want) {
+				t.Errorf("problems = %q, want %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateExternalIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    []Issue
+	}{
+		{
+			name:    "unique",
+			entries: []Entry{{ExternalID: "m1", Row: 2}, {ExternalID: "m2", Row: 3}},
+		},
+		{
+			name: "reused",
+			entries: []Entry{
+				{ExternalID: "m1", Row: 2},
+				{ExternalID: "m2", Row: 3},
+				{ExternalID: " m1 ", Row: 4},
+				{ExternalID: "m1", Row: 5},
+			},
+			want: []Issue{
+				{Row: 4, Message: "external_id is already used by row 2"},
+				{Row: 5, Message: "external_id is already used by row 2"},
+			},
+		},
+		{
+			name:    "missing IDs are left to Validate",
+			entries: []Entry{{Row: 2}, {ExternalID: " ", Row: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if issues := DuplicateExternalIDs(tt.entries); !reflect.DeepEqual(issues, tt.want) {
+				t.Errorf("issues = %+v, want %+v", issues, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadJSON reads a bundle written as a list of entries, or as {"movies": [...]}
+func ReadJSON(r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	var entries []Entry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var bundle struct {
+			Movies []Entry `json:"movies"`
+		}
+		err = json.Unmarshal(data, &bundle)
+		entries = bundle.Movies
+	} else {
+		err = json.Unmarshal(data, &entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	for i := range entries {
+		entries[i].Row = i + 1
+	}
+	return entries, nil
+}
+
+// ReadCSV reads a bundle with one movie per row. Genres and cast are "|" separated, and
+// localizations go in "title:<code>" and "description:<code>" columns, e.g. "title:ta".
+// Like other empty cells, an empty cast leaves the movie's cast as it is. Rows that
+// can't be read are returned as issues.
+func ReadCSV(r io.Reader) ([]Entry, []Issue, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	var languages []string
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+		if code, ok := strings.CutPrefix(name, "title:"); ok {
+			languages = append(languages, code)
+		}
+	}
+	sort.Strings(languages)
+	for _, required := range []string{"external_id", "original_title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV is missing the %q column", required)
+		}
+	}
+
+	var entries []Entry
+	var issues []Issue
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := Entry{
+			ExternalID:     column("external_id"),
+			OriginalTitle:  column("original_title"),
+			ReleaseDate:    column("release_date"),
+			Rating:         column("rating"),
+			Description:    column("description"),
+			PosterURL:      column("poster_url"),
+			ComingSoonAt:   column("coming_soon_at"),
+			BookingOpensAt: column("booking_opens_at"),
+			Genres:         splitList(column("genres")),
+			Cast:           splitList(column("cast")),
+			Row:            line,
+		}
+
+		if duration := column("duration_minutes"); duration != "" {
+			if entry.DurationMinutes, err = strconv.Atoi(duration); err != nil {
+				issues = append(issues, Issue{Row: line, Message: fmt.Sprintf("duration_minutes %q is not a number", duration)})
+				continue
+			}
+		}
+
+		for _, code := range languages {
+			title, description := column("title:"+code), column("description:"+code)
+			if title == "" && description == "" {
+				continue
+			}
+			entry.Localizations = append(entry.Localizations, Localization{Language: code, Title: title, Description: description})
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, issues, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -16,15 +17,104 @@ type Config struct {
 	Port        string
 	Environment string
 	RedisURL    string
+
+	TicketSigningKey string // hex encoded ed25519 seed used to sign ticket QR codes
+	PaymentProvider  string
+
+	// Accessible seats only open to everyone this close to the show
+	AccessibleSeatReleaseMinutes int
+
+	// Default lead times before release for movies that don't set their own dates
+	MovieComingSoonDays     int
+	MovieAdvanceBookingDays int
+
+	// Localization. Fallbacks list, per language, what to try next when content is
+	// missing, e.g. "ml:ta,en;te:en". DefaultLanguage is tried last and is the
+	// language untranslated content is written in.
+	DefaultLanguage   string
+	LanguageFallbacks string
+
+	// Age gating. Theaters without a country use DefaultCountry's certifications. With
+	// AgeGateRequireVerified a declared date of birth isn't enough for restricted shows,
+	// staff must have verified the customer's age.
+	DefaultCountry         string
+	AgeGateRequireVerified bool
+
+	// Login brute-force protection
+	LoginMaxAttempts     int // Failed attempts per account before it is locked
+	LoginMaxIPAttempts   int // Failed attempts per IP before it is blocked
+	LoginWindowMinutes   int // How long failed attempts are remembered
+	LoginLockoutMinutes  int
+	LoginMaxDelaySeconds int // Cap for the progressive delay between attempts
+
+	// Password policy
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordHistorySize   int    // Recent passwords that can't be reused, including the current one
+	PasswordMaxAgeDays    int    // Forced rotation, 0 disables
+	PasswordBlocklistFile string // Extra breached passwords on top of the bundled list, one per line
+
+	// Requests per minute for each rate limit policy, 0 disables a policy
+	RateLimitCatalogPerMinute int // Public catalog, per API key, user or IP
+	RateLimitAuthPerMinute    int // Login and account recovery, per IP
+	RateLimitUserPerMinute    int // Logged-in routes, per API key or user
+	RateLimitIPPerMinute      int // Logged-in routes before authentication, per IP
+
+	// Outgoing mail
+	MailDriver   string // log, file or smtp
+	MailFrom     string
+	MailDir      string // Where the file driver writes messages
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	NotificationMaxAttempts int // Sends of an outbox notification before it is marked failed
+
+	AppBaseURL  string // Frontend URL used in links sent by email
+	TokenSecret string // HMAC key for password reset and email verification tokens
+
+	// OpenID Connect single sign-on, disabled when the issuer is empty
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+	OIDCScopes       string
+	OIDCGroupsClaim  string
+	OIDCRoleMapping  string // IdP group to role, e.g. "cinema-admins=admin". Only admin is supported.
+	OIDCDefaultRole  string // Role for users in no mapped group, empty refuses them; admin if set
+
+	// File storage for uploads. "local" keeps files under StorageDir, "s3" uses any
+	// S3 compatible object store.
+	StorageDriver string
+	StorageDir    string
+	S3Endpoint    string // e.g. https://s3.ap-south-1.amazonaws.com or a MinIO URL
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+
+	// Media assets are served through signed URLs under MediaBaseURL, valid for
+	// MediaURLTTLHours
+	MediaSigningKey  string
+	MediaBaseURL     string
+	MediaURLTTLHours int
+	MediaMaxImageMB  int
 }
 
+// App is the loaded configuration, for packages that read settings at request time
+var App *Config
+
 func Load() *Config {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	return &Config{
+	App = &Config{
 		DBHost:      getEnv("DB_HOST", "localhost"),
 		DBPort:      getEnv("DB_PORT", "5432"),
 		DBUser:      getEnv("DB_USER", "postgres"),
@@ -33,7 +123,78 @@ func Load() *Config {
 		Port:        getEnv("PORT", "8080"),
 		Environment: getEnv("ENVIRONMENT", "development"),
 		RedisURL:    getEnv("REDIS_URL", "redis://localhost:6379"),
+
+		TicketSigningKey: getEnv("TICKET_SIGNING_KEY", ""),
+		PaymentProvider:  getEnv("PAYMENT_PROVIDER", "manual"),
+
+		AccessibleSeatReleaseMinutes: getEnvInt("ACCESSIBLE_SEAT_RELEASE_MINUTES", 120),
+
+		MovieComingSoonDays:     getEnvInt("MOVIE_COMING_SOON_DAYS", 30),
+		MovieAdvanceBookingDays: getEnvInt("MOVIE_ADVANCE_BOOKING_DAYS", 7),
+
+		DefaultLanguage:   getEnv("DEFAULT_LANGUAGE", "en"),
+		LanguageFallbacks: getEnv("LANGUAGE_FALLBACKS", ""),
+
+		DefaultCountry:         getEnv("DEFAULT_COUNTRY", "IN"),
+		AgeGateRequireVerified: getEnvBool("AGE_GATE_REQUIRE_VERIFIED", false),
+
+		LoginMaxAttempts:     getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginMaxIPAttempts:   getEnvInt("LOGIN_MAX_IP_ATTEMPTS", 20),
+		LoginWindowMinutes:   getEnvInt("LOGIN_WINDOW_MINUTES", 15),
+		LoginLockoutMinutes:  getEnvInt("LOGIN_LOCKOUT_MINUTES", 15),
+		LoginMaxDelaySeconds: getEnvInt("LOGIN_MAX_DELAY_SECONDS", 30),
+
+		PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 10),
+		PasswordRequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", true),
+		PasswordRequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		PasswordRequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordHistorySize:   getEnvInt("PASSWORD_HISTORY_SIZE", 5),
+		PasswordMaxAgeDays:    getEnvInt("PASSWORD_MAX_AGE_DAYS", 0),
+		PasswordBlocklistFile: getEnv("PASSWORD_BLOCKLIST_FILE", ""),
+
+		RateLimitCatalogPerMinute: getEnvInt("RATE_LIMIT_CATALOG_PER_MINUTE", 120),
+		RateLimitAuthPerMinute:    getEnvInt("RATE_LIMIT_AUTH_PER_MINUTE", 10),
+		RateLimitUserPerMinute:    getEnvInt("RATE_LIMIT_USER_PER_MINUTE", 300),
+		RateLimitIPPerMinute:      getEnvInt("RATE_LIMIT_IP_PER_MINUTE", 1200),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "log"),
+		MailFrom:     getEnv("MAIL_FROM", "Vanam <noreply@vanam.com>"),
+		MailDir:      getEnv("MAIL_DIR", "./tmp/mail"),
+		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		NotificationMaxAttempts: getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+
+		AppBaseURL:  getEnv("APP_BASE_URL", "http://localhost:3000"),
+		TokenSecret: getEnv("TOKEN_SECRET", ""),
+
+		OIDCIssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:3000/auth/callback"),
+		OIDCScopes:       getEnv("OIDC_SCOPES", "openid email profile groups"),
+		OIDCGroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCRoleMapping:  getEnv("OIDC_ROLE_MAPPING", ""),
+		OIDCDefaultRole:  getEnv("OIDC_DEFAULT_ROLE", ""),
+
+		StorageDriver: getEnv("STORAGE_DRIVER", "local"),
+		StorageDir:    getEnv("STORAGE_DIR", "./storage"),
+		S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:      getEnv("S3_BUCKET", ""),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+
+		MediaSigningKey:  getEnv("MEDIA_SIGNING_KEY", ""),
+		MediaBaseURL:     getEnv("MEDIA_BASE_URL", ""),
+		MediaURLTTLHours: getEnvInt("MEDIA_URL_TTL_HOURS", 168),
+		MediaMaxImageMB:  getEnvInt("MEDIA_MAX_IMAGE_MB", 10),
 	}
+
+	return App
 }
 
 func getEnv(key, fallback string) string {
@@ -42,3 +203,23 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %d", key, fallback)
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %t", key, fallback)
+	}
+	return fallback
+}
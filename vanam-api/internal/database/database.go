@@ -38,6 +38,14 @@ func Connect(cfg *config.Config) {
 }
 
 func Migrate() {
+	// movie_cast carries each credit's role and billing order
+	if err := DB.SetupJoinTable(&models.Movie{}, "Cast", &models.MovieCast{}); err != nil {
+		log.Fatal("Failed to set up movie_cast:", err)
+	}
+	if err := DB.SetupJoinTable(&models.Person{}, "Movies", &models.MovieCast{}); err != nil {
+		log.Fatal("Failed to set up movie_cast:", err)
+	}
+
 	err := DB.AutoMigrate(
 		&models.Role{},
 		&models.User{},
@@ -46,19 +54,105 @@ func Migrate() {
 		&models.Language{},
 		&models.Movie{},
 		&models.MovieLanguage{},
+		&models.GenreLanguage{},
+		&models.RatingDescription{},
+		&models.CertificationSystem{},
+		&models.Certification{},
+		&models.MovieCertification{},
+		&models.ContentAdvisory{},
+		&models.MediaAsset{},
+		&models.MediaVariant{},
 		&models.Screen{},
 		&models.Screening{},
 		&models.Theater{},
 		&models.Seat{},
+		&models.Booking{},
+		&models.Ticket{},
+		&models.CancellationPolicy{},
+		&models.CancellationTier{},
+		&models.BookingCancellation{},
+		&models.Refund{},
+		&models.ScreeningCancellation{},
+		&models.RebookOffer{},
+		&models.Notification{},
+		&models.SecurityEvent{},
+		&models.RecoveryCode{},
+		&models.PasswordHistory{},
+		&models.APIKey{},
+		&models.MergeRecord{},
 	)
 
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	createSearchIndexes()
+	migrateMovieCastKey()
+
 	log.Println("✅ Database migration completed")
 }
 
+// migrateMovieCastKey adds role to the movie_cast primary key of databases created
+// before credits had roles, so one person can be both director and writer. AutoMigrate
+// adds the column but doesn't change keys.
+func migrateMovieCastKey() {
+	var keyed int64
+	if err := DB.Raw(`SELECT COUNT(*) FROM information_schema.key_column_usage
+		WHERE table_name = 'movie_cast' AND constraint_name = 'movie_cast_pkey' AND column_name = 'role'`).
+		Scan(&keyed).Error; err != nil || keyed > 0 {
+		return
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE movie_cast DROP CONSTRAINT IF EXISTS movie_cast_pkey").Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE movie_cast ADD PRIMARY KEY (movie_id, person_id, role)").Error
+	}); err != nil {
+		log.Printf("⚠️  Failed to migrate movie_cast primary key: %v", err)
+	}
+}
+
+// TrigramSearch is whether the pg_trgm extension is available. Without it search falls
+// back to full-text and substring matching, and duplicate detection is unavailable.
+var TrigramSearch bool
+
+// Search uses pg_trgm for fuzzy matching and tsvector expressions for full-text matching.
+// The expressions here must match the ones in the search handlers for the indexes to be used.
+var searchIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_movies_search_tsv ON movies USING GIN (to_tsvector('simple', coalesce(original_title, '') || ' ' || coalesce(description, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_movie_languages_search_tsv ON movie_languages USING GIN (to_tsvector('simple', coalesce(title, '')))",
+}
+
+var trigramIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_movies_title_trgm ON movies USING GIN (lower(original_title) gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_movie_languages_title_trgm ON movie_languages USING GIN (lower(title) gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_people_name_trgm ON people USING GIN (lower(name) gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_theaters_name_trgm ON theaters USING GIN (lower(name) gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_theaters_city_trgm ON theaters USING GIN (lower(city) gin_trgm_ops)",
+}
+
+func createSearchIndexes() {
+	for _, statement := range searchIndexes {
+		if err := DB.Exec(statement).Error; err != nil {
+			log.Fatal("Failed to create search indexes:", err)
+		}
+	}
+
+	// Creating the extension needs a privileged role on most managed databases, so a
+	// missing extension degrades search instead of stopping the server
+	if err := DB.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		log.Printf("⚠️  The pg_trgm extension is not available, search will not match misspellings and duplicate detection is disabled. Run CREATE EXTENSION pg_trgm as a superuser to enable them: %v", err)
+		return
+	}
+	for _, statement := range trigramIndexes {
+		if err := DB.Exec(statement).Error; err != nil {
+			log.Fatal("Failed to create search indexes:", err)
+		}
+	}
+	TrigramSearch = true
+}
+
 func SeedData() {
 	// Start transaction
 	tx := DB.Begin()
@@ -77,6 +171,9 @@ func SeedData() {
 	// Seed Languages
 	seedLanguages(tx)
 
+	// Seed Certification Systems
+	seedCertifications(tx)
+
 	// Seed Sample Persons (Actors/Directors)
 	seedPersons(tx)
 
@@ -182,6 +279,45 @@ func seedLanguages(tx *gorm.DB) {
 	log.Printf("✅ Languages seeded successfully")
 }
 
+func seedCertifications(tx *gorm.DB) {
+	systems := []models.CertificationSystem{
+		{Country: "IN", Name: "Central Board of Film Certification", Certifications: []models.Certification{
+			{Code: "U", Name: "Unrestricted public exhibition", SortOrder: 1},
+			{Code: "U/A", Name: "Parental guidance for children under 12", MinAge: 12, SortOrder: 2},
+			{Code: "U/A 7+", Name: "Parental guidance for children under 7", MinAge: 7, SortOrder: 3},
+			{Code: "U/A 13+", Name: "Parental guidance for children under 13", MinAge: 13, SortOrder: 4},
+			{Code: "U/A 16+", Name: "Parental guidance for children under 16", MinAge: 16, SortOrder: 5},
+			{Code: "A", Name: "Restricted to adults", MinAge: 18, Restricted: true, SortOrder: 6},
+			{Code: "S", Name: "Restricted to specialised audiences", SortOrder: 7},
+		}},
+		{Country: "US", Name: "Motion Picture Association", Certifications: []models.Certification{
+			{Code: "G", Name: "General audiences", SortOrder: 1},
+			{Code: "PG", Name: "Parental guidance suggested", SortOrder: 2},
+			{Code: "PG-13", Name: "Parents strongly cautioned", MinAge: 13, SortOrder: 3},
+			{Code: "R", Name: "Restricted, under 17 requires accompanying parent or adult guardian", MinAge: 17, SortOrder: 4},
+			{Code: "NC-17", Name: "No one 17 and under admitted", MinAge: 18, Restricted: true, SortOrder: 5},
+		}},
+		{Country: "GB", Name: "British Board of Film Classification", Certifications: []models.Certification{
+			{Code: "U", Name: "Universal", SortOrder: 1},
+			{Code: "PG", Name: "Parental guidance", SortOrder: 2},
+			{Code: "12A", Name: "Under 12s must be accompanied by an adult", MinAge: 12, SortOrder: 3},
+			{Code: "15", Name: "Suitable only for 15 years and over", MinAge: 15, Restricted: true, SortOrder: 4},
+			{Code: "18", Name: "Suitable only for adults", MinAge: 18, Restricted: true, SortOrder: 5},
+		}},
+	}
+
+	for _, system := range systems {
+		var existing models.CertificationSystem
+		if err := tx.Where("country = ?", system.Country).First(&existing).Error; err == gorm.ErrRecordNotFound {
+			if err := tx.Create(&system).Error; err != nil {
+				log.Printf("Failed to create certification system %s: %v", system.Country, err)
+			}
+		}
+	}
+
+	log.Printf("✅ Certification systems seeded successfully")
+}
+
 func seedPersons(tx *gorm.DB) {
 	persons := []models.Person{
 		{Name: "Christopher Nolan", Bio: "British-American filmmaker known for his complex narratives"},
@@ -243,6 +379,8 @@ func seedAdminUser(tx *gorm.DB) {
 			Name:     "System Administrator",
 			RoleID:   adminRole.ID,
 			IsActive: true,
+
+			MustChangePassword: true,
 		}
 
 		if err := tx.Create(&adminUser).Error; err != nil {
@@ -252,5 +390,13 @@ func seedAdminUser(tx *gorm.DB) {
 		}
 	} else {
 		log.Println("✅ Admin user already exists")
+
+		// An admin still on the published default password must change it on next login
+		var adminUser models.User
+		if err := tx.Where("email = ?", "admin@vanam.com").First(&adminUser).Error; err == nil &&
+			!adminUser.MustChangePassword && utils.CheckPasswordHash("vanam", adminUser.Password) {
+			tx.Model(&adminUser).Update("must_change_password", true)
+			log.Println("⚠️  Admin user still uses the default password, a password change will be required")
+		}
 	}
 }
@@ -5,3 +5,15 @@ type LanguageRequest struct {
 	Name       string `json:"name" binding:"required,min=1,max=100"`
 	NativeName string `json:"native_name"`
 }
+
+type GenreLanguageRequest struct {
+	LanguageID uint   `json:"language_id" binding:"required"`
+	Name       string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type RatingDescriptionRequest struct {
+	Rating      string `json:"rating" binding:"required,max=20"` // A default country certification code
+	LanguageID  uint   `json:"language_id" binding:"required"`
+	Label       string `json:"label" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
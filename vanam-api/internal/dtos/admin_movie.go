@@ -15,23 +15,57 @@ type CreateMovieRequest struct {
 	OriginalTitle string      `json:"original_title" binding:"required,min=1,max=255"`
 	Duration      int         `json:"duration_minutes" binding:"required,min=1"`
 	ReleaseDate   time.Time   `json:"release_date" binding:"required"`
-	Rating        MovieRating `json:"rating" binding:"required"`
+	Rating        MovieRating `json:"rating" binding:"required,max=20"` // A default country certification code
 	Description   string      `json:"description"`
 	PosterURL     string      `json:"poster_url" binding:"max=500"`
 	GenreIDs      []uint      `json:"genre_ids" binding:"required,min=1"`
 	CastIDs       []uint      `json:"cast_ids"`
+
+	// Lifecycle dates, defaulting to configured lead times before the release
+	ComingSoonAt   *time.Time `json:"coming_soon_at"`
+	BookingOpensAt *time.Time `json:"booking_opens_at"`
+
+	IMDbID             string          `json:"imdb_id" binding:"omitempty,startswith=tt,max=20"`
+	TMDBID             *int            `json:"tmdb_id" binding:"omitempty,min=1"`
+	OriginalLanguageID *uint           `json:"original_language_id"`
+	CountryOfOrigin    string          `json:"country_of_origin" binding:"omitempty,len=2,alpha"`
+	Credits            []CreditRequest `json:"credits" binding:"omitempty,dive"` // Replaces cast_ids when given
 }
 
 type UpdateMovieRequest struct {
 	OriginalTitle *string      `json:"original_title,omitempty" binding:"omitempty,min=1,max=255"`
 	Duration      *int         `json:"duration_minutes,omitempty" binding:"omitempty,min=1"`
 	ReleaseDate   *time.Time   `json:"release_date,omitempty"`
-	Rating        *MovieRating `json:"rating,omitempty"`
+	Rating        *MovieRating `json:"rating,omitempty" binding:"omitempty,max=20"`
 	Description   *string      `json:"description,omitempty"`
 	PosterURL     *string      `json:"poster_url,omitempty" binding:"omitempty,max=500"`
 	GenreIDs      []uint       `json:"genre_ids,omitempty"`
 	CastIDs       []uint       `json:"cast_ids,omitempty"`
 	IsActive      *bool        `json:"is_active,omitempty"`
+
+	ComingSoonAt   *time.Time `json:"coming_soon_at,omitempty"`
+	BookingOpensAt *time.Time `json:"booking_opens_at,omitempty"`
+	Status         *string    `json:"status,omitempty" binding:"omitempty,oneof=announced coming_soon advance_booking now_showing ended"` // Locks the status
+	AutoStatus     bool       `json:"auto_status,omitempty"`                                                                              // Hands the status back to the job
+
+	IMDbID             *string         `json:"imdb_id,omitempty" binding:"omitempty,max=20"`          // Empty clears it
+	TMDBID             *int            `json:"tmdb_id,omitempty" binding:"omitempty,min=0"`           // 0 clears it
+	OriginalLanguageID *uint           `json:"original_language_id,omitempty"`                        // 0 clears it
+	CountryOfOrigin    *string         `json:"country_of_origin,omitempty" binding:"omitempty,max=2"` // Empty clears it
+	Credits            []CreditRequest `json:"credits,omitempty" binding:"omitempty,dive"`
+}
+
+// CreditRequest credits a person, given by ID or by name, matched or created as a Person
+type CreditRequest struct {
+	PersonID      uint   `json:"person_id"`
+	PersonName    string `json:"person_name" binding:"max=255"`
+	Role          string `json:"role" binding:"required,oneof=actor director writer music_director producer cinematographer editor"`
+	CharacterName string `json:"character_name" binding:"max=255"`
+	BillingOrder  int    `json:"billing_order" binding:"min=0"`
+}
+
+type MovieCreditsRequest struct {
+	Credits []CreditRequest `json:"credits" binding:"dive"`
 }
 
 type MovieLanguageRequest struct {
@@ -81,4 +115,5 @@ type ScreeningFilters struct {
 	Date       string `form:"date"`
 	TheaterID  *uint  `form:"theater_id"`
 	ScreenID   *uint  `form:"screen_id"`
+	Accessible *bool  `form:"accessible"` // Only screenings with free accessible seats
 }
@@ -1,9 +1,11 @@
 package dtos
 
 type CreateRoleRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=50"`
+	Name             string `json:"name" binding:"required,min=2,max=50"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
 }
 
 type UpdateRoleRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=50"`
+	Name             string `json:"name" binding:"required,min=2,max=50"`
+	RequireTwoFactor *bool  `json:"require_two_factor,omitempty"`
 }
@@ -6,4 +6,5 @@ type TheaterRequest struct {
 	City     string `json:"city"`
 	State    string `json:"state"`
 	IsActive *bool  `json:"is_active"` // Pointer to allow null values
+	Country  string `json:"country" binding:"omitempty,len=2,alpha"`
 }
@@ -3,14 +3,23 @@ package dtos
 type CreateUserRequest struct {
 	Name     string `json:"name" binding:"required,min=2,max=100"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
+	Password string `json:"password" binding:"required"` // Checked against the password policy
 	RoleID   uint   `json:"role_id" binding:"required"`
 }
 
 type UpdateUserRequest struct {
 	Name     string `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
 	Email    string `json:"email,omitempty" binding:"omitempty,email"`
-	Password string `json:"password,omitempty" binding:"omitempty,min=8"`
+	Password string `json:"password,omitempty"`
 	RoleID   uint   `json:"role_id,omitempty"`
 	IsActive *bool  `json:"is_active,omitempty"`
 }
+
+type ImpersonateUserRequest struct {
+	Reason          string `json:"reason" binding:"required,min=5,max=500"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=1,max=60"`
+}
+
+type UpdatePreferencesRequest struct {
+	PreferredLanguage *string `json:"preferred_language" binding:"omitempty,max=10"` // Empty clears the preference
+}
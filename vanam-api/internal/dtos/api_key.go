@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name               string     `json:"name" binding:"required,min=2,max=100"`
+	Scopes             []string   `json:"scopes" binding:"required,min=1"`
+	UserID             uint       `json:"user_id"` // Defaults to the caller
+	RateLimitPerMinute *int       `json:"rate_limit_per_minute" binding:"omitempty,min=0,max=100000"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+}
+
+type UpdateAPIKeyRequest struct {
+	Name               *string    `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	Scopes             []string   `json:"scopes,omitempty" binding:"omitempty,min=1"`
+	RateLimitPerMinute *int       `json:"rate_limit_per_minute,omitempty" binding:"omitempty,min=0,max=100000"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	ClearExpiry        bool       `json:"clear_expiry"`
+}
@@ -0,0 +1,49 @@
+package dtos
+
+type CreateBookingRequest struct {
+	ScreeningID   uint   `json:"screening_id" binding:"required"`
+	SeatIDs       []uint `json:"seat_ids" binding:"required,min=1,max=10"`
+	Accessible    bool   `json:"accessible"`     // Customer needs accessible seating
+	WithCompanion bool   `json:"with_companion"` // Add a companion seat beside each accessible seat
+}
+
+type TicketScanRequest struct {
+	Code        string `json:"code" binding:"required"`
+	ScreeningID uint   `json:"screening_id" binding:"required"` // Screening the usher is admitting for
+	SeatNumber  string `json:"seat_number"`                     // Optional seat printed on the ticket
+}
+
+type CancelBookingRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type AdminCancelBookingRequest struct {
+	Reason        string   `json:"reason" binding:"required,max=500"`
+	RefundPercent *float64 `json:"refund_percent" binding:"omitempty,min=0,max=100"` // Defaults to a full refund
+}
+
+type CancellationTierRequest struct {
+	HoursBefore   int     `json:"hours_before" binding:"min=0"`
+	RefundPercent float64 `json:"refund_percent" binding:"min=0,max=100"`
+}
+
+type CancellationPolicyRequest struct {
+	Name          string                    `json:"name" binding:"required,min=1,max=100"`
+	TheaterID     *uint                     `json:"theater_id"`
+	ScreeningID   *uint                     `json:"screening_id"`
+	IsDefault     bool                      `json:"is_default"`
+	CutoffMinutes int                       `json:"cutoff_minutes" binding:"min=0"`
+	IsActive      *bool                     `json:"is_active"`
+	Tiers         []CancellationTierRequest `json:"tiers" binding:"required,min=1,dive"`
+}
+
+type CancelScreeningRequest struct {
+	Reason     string `json:"reason" binding:"required,min=3,max=500"`
+	Mode       string `json:"mode" binding:"omitempty,oneof=refund rebook"` // Defaults to refund
+	OfferHours int    `json:"offer_hours" binding:"omitempty,min=1,max=720"`
+}
+
+type RebookRequest struct {
+	ScreeningID uint   `json:"screening_id" binding:"required"`
+	SeatIDs     []uint `json:"seat_ids" binding:"required,min=1,max=10"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+// CatalogImportRow is what an import did, or would do, with one movie of a bundle
+type CatalogImportRow struct {
+	Row        int      `json:"row"`
+	ExternalID string   `json:"external_id"`
+	Title      string   `json:"title"`
+	Action     string   `json:"action"` // "create", "update", "unchanged" or "error"
+	MovieID    uint     `json:"movie_id,omitempty"`
+	Changes    []string `json:"changes,omitempty"`    // Fields an update changes
+	NewPeople  []string `json:"new_people,omitempty"` // Cast members not matched to an existing person
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// CatalogImportReport previews or summarises a catalog import
+type CatalogImportReport struct {
+	Applied   bool               `json:"applied"`
+	Rows      int                `json:"rows"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+	Results   []CatalogImportRow `json:"results"`
+}
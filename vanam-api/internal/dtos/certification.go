@@ -0,0 +1,31 @@
+package dtos
+
+type CertificationSystemRequest struct {
+	Country  string `json:"country" binding:"required,len=2,alpha"`
+	Name     string `json:"name" binding:"required,min=1,max=100"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type CertificationRequest struct {
+	Code        string `json:"code" binding:"required,min=1,max=20"`
+	Name        string `json:"name" binding:"max=100"`
+	Description string `json:"description" binding:"max=500"`
+	MinAge      int    `json:"min_age" binding:"min=0,max=21"`
+	Restricted  bool   `json:"restricted"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+type MovieCertificationRequest struct {
+	CertificationID   uint   `json:"certification_id" binding:"required"`
+	CertificateNumber string `json:"certificate_number" binding:"max=50"`
+}
+
+type ContentAdvisoryRequest struct {
+	Category    string `json:"category" binding:"required,oneof=violence language sexual_content drugs frightening discrimination flashing_lights"`
+	Severity    string `json:"severity" binding:"required,oneof=mild moderate strong"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type DateOfBirthRequest struct {
+	DateOfBirth string `json:"date_of_birth" binding:"required"` // YYYY-MM-DD
+}
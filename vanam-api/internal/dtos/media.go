@@ -0,0 +1,15 @@
+package dtos
+
+type TrailerRequest struct {
+	Title           string `json:"title" binding:"required,min=1,max=200"`
+	VideoURL        string `json:"video_url" binding:"required,url"`
+	DurationSeconds int    `json:"duration_seconds" binding:"min=0"`
+	LanguageID      *uint  `json:"language_id"`
+	IsPrimary       bool   `json:"is_primary"`
+}
+
+type UpdateMediaAssetRequest struct {
+	IsPrimary *bool   `json:"is_primary"`
+	SortOrder *int    `json:"sort_order"`
+	Title     *string `json:"title" binding:"omitempty,min=1,max=200"`
+}
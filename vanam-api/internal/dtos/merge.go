@@ -0,0 +1,47 @@
+package dtos
+
+type MergeRequest struct {
+	Kind        string `json:"kind" binding:"required,oneof=movie person"`
+	SurvivorID  uint   `json:"survivor_id" binding:"required"`
+	DuplicateID uint   `json:"duplicate_id" binding:"required,nefield=SurvivorID"`
+}
+
+// DuplicateRecord is one side of a likely duplicate pair
+type DuplicateRecord struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`           // Original title for movies
+	Year       int    `json:"year,omitempty"` // Release year for movies
+	Credits    int    `json:"credits"`
+	Screenings int    `json:"screenings,omitempty"`
+}
+
+// DuplicateCandidate is a pair of movies or people that are probably the same. Score is
+// between 0 and 1; SuggestedSurvivorID is the record more of the catalog points at.
+type DuplicateCandidate struct {
+	Kind                string            `json:"kind"`
+	Records             []DuplicateRecord `json:"records"`
+	Score               float64           `json:"score"`
+	NameSimilarity      float64           `json:"name_similarity"`
+	YearGap             *int              `json:"year_gap,omitempty"`
+	SharedCast          int               `json:"shared_cast"`             // Movies: people credited on both. People: collaborators in common
+	SharedMovies        int               `json:"shared_movies,omitempty"` // People credited on the same movie
+	SuggestedSurvivorID uint              `json:"suggested_survivor_id"`
+}
+
+// MergeSummary counts what a merge moved or undid
+type MergeSummary struct {
+	MergeID        uint   `json:"merge_id"`
+	Kind           string `json:"kind"`
+	SurvivorID     uint   `json:"survivor_id"`
+	DuplicateID    uint   `json:"duplicate_id"`
+	Credits        int    `json:"credits"`
+	DroppedCredits int    `json:"dropped_credits"`
+	Genres         int    `json:"genres,omitempty"`
+	Languages      int    `json:"languages,omitempty"`
+	KeptLanguages  int    `json:"kept_languages,omitempty"` // Localizations left on the duplicate
+	Screenings     int    `json:"screenings,omitempty"`
+	Assets         int    `json:"assets,omitempty"`
+	Certifications int    `json:"certifications,omitempty"`
+	Advisories     int    `json:"advisories,omitempty"`
+	ExternalID     bool   `json:"external_id,omitempty"` // The survivor took the duplicate's external ID
+}
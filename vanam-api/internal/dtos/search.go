@@ -0,0 +1,18 @@
+package dtos
+
+// SearchResult is one ranked hit from the unified search
+type SearchResult struct {
+	Type     string  `json:"type"` // "movie", "person" or "theater"
+	ID       uint    `json:"id"`
+	Title    string  `json:"title"`
+	Subtitle string  `json:"subtitle,omitempty"` // Release year for movies, city for theaters
+	ImageURL string  `json:"image_url,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// SearchSuggestion is one autocomplete entry
+type SearchSuggestion struct {
+	Type string `json:"type"`
+	ID   uint   `json:"id"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,25 @@
+package dtos
+
+import "github.com/prabalesh/vanam/vanam-api/internal/translations"
+
+// TranslationChange is one field an import creates or changes
+type TranslationChange struct {
+	Key      string `json:"key"`
+	Language string `json:"language"`
+	Action   string `json:"action"` // "create" or "update"
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new"`
+}
+
+// TranslationImportReport previews or summarises a translation import
+type TranslationImportReport struct {
+	Applied   bool                 `json:"applied"`
+	Units     int                  `json:"units"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Skipped   int                  `json:"skipped"` // Left untranslated in the file
+	Changes   []TranslationChange  `json:"changes"`
+	Conflicts []translations.Issue `json:"conflicts"`
+	Errors    []translations.Issue `json:"errors"`
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/mailer"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+	"github.com/prabalesh/vanam/vanam-api/internal/tokens"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+)
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+	resetRequestCooldown = time.Minute
+	mailSendTimeout      = 30 * time.Second
+)
+
+// RequestPasswordReset - Email a password reset link. Always succeeds so accounts can't be enumerated.
+func RequestPasswordReset(c *gin.Context) {
+	var req dtos.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	response := utils.SuccessResponse("If an account exists for that email, a reset link has been sent", nil)
+
+	// One request per address per cooldown, so the endpoint can't be used to flood an inbox
+	cooldownKey := "password_reset:cooldown:" + strings.ToLower(req.Email)
+	if set, err := redis.Client.SetNX(redis.Ctx, cooldownKey, 1, resetRequestCooldown).Result(); err != nil || !set {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := tokens.Issue(tokens.PasswordReset, user.ID, user.Email, passwordResetTTL)
+	if err != nil {
+		log.Printf("Failed to issue password reset token: %v", err)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	recordSecurityEvent(c, models.EventPasswordResetRequested, &user.ID, user.Email, "")
+	sendMail(user.Email, "Reset your Vanam password", fmt.Sprintf(
+		"Hi %s,\n\nWe received a request to reset your password. Use the link below within the next hour:\n\n%s\n\nIf you didn't ask for this, you can ignore this email.",
+		user.Name, appLink("/reset-password", token)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ConfirmPasswordReset - Set a new password with a reset token
+func ConfirmPasswordReset(c *gin.Context) {
+	var req dtos.PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	// Only peek at the token first, so a password the policy rejects doesn't burn the link
+	claims, err := tokens.Peek(tokens.PasswordReset, req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Reset link is invalid or has expired"))
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ? AND is_active = ?", claims.UserID, true).First(&user).Error; err != nil || user.Email != claims.Email {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Reset link is invalid or has expired"))
+		return
+	}
+
+	if rejectWeakPassword(c, "new_password", req.NewPassword, user) {
+		return
+	}
+
+	if _, err := tokens.Consume(tokens.PasswordReset, req.Token); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Reset link is invalid or has expired"))
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to hash password"))
+		return
+	}
+
+	// The link reached the user's inbox, which also proves they own the address
+	updates := map[string]interface{}{}
+	if !user.EmailVerified {
+		updates["email_verified"] = true
+		updates["email_verified_at"] = time.Now()
+	}
+	if err := setPassword(user, hashedPassword, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to reset password"))
+		return
+	}
+
+	// Whoever held the old password is logged out and any lockout is lifted
+	revoked, _ := sessions.RevokeAll(user.ID)
+	security.Unlock(user.Email)
+	recordSecurityEvent(c, models.EventPasswordReset, &user.ID, user.Email, fmt.Sprintf("%d sessions revoked", revoked))
+
+	sendMail(user.Email, "Your Vanam password was changed",
+		fmt.Sprintf("Hi %s,\n\nYour password was just reset. If this wasn't you, contact an administrator immediately.", user.Name))
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Password reset successfully, please log in", nil))
+}
+
+// VerifyEmail - Confirm an email address with a verification token
+func VerifyEmail(c *gin.Context) {
+	var req dtos.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	claims, err := tokens.Consume(tokens.EmailVerification, req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Verification link is invalid or has expired"))
+		return
+	}
+
+	// The address may have changed since the link was sent
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil || user.Email != claims.Email {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Verification link is invalid or has expired"))
+		return
+	}
+
+	if err := database.DB.Model(&user).Updates(map[string]interface{}{
+		"email_verified":    true,
+		"email_verified_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to verify email"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventEmailVerified, &user.ID, user.Email, "")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Email verified successfully", nil))
+}
+
+// ResendVerificationEmail - Send a new verification link to the logged-in user
+func ResendVerificationEmail(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	if user.EmailVerified {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Email is already verified"))
+		return
+	}
+
+	if err := sendVerificationEmail(user); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to send verification email"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Verification email sent", nil))
+}
+
+// sendVerificationEmail issues a verification token and mails the link
+func sendVerificationEmail(user models.User) error {
+	token, err := tokens.Issue(tokens.EmailVerification, user.ID, user.Email, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	sendMail(user.Email, "Verify your email for Vanam", fmt.Sprintf(
+		"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link is valid for 48 hours.",
+		user.Name, appLink("/verify-email", token)))
+	return nil
+}
+
+// sendMail delivers security mail directly rather than through the outbox, so tokens
+// are never stored in the database. It runs in the background so response times
+// don't reveal whether an account exists.
+func sendMail(to, subject, body string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+
+		if err := mailer.Default.Send(ctx, mailer.Message{To: to, Subject: subject, Body: body}); err != nil {
+			log.Printf("❌ Failed to send mail %q: %v", subject, err)
+		}
+	}()
+}
+
+// appLink builds a frontend URL carrying a token
+func appLink(path, token string) string {
+	return config.App.AppBaseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const defaultAPIKeyRateLimit = 60
+
+// CreateAPIKey - Issue a scoped API key. The key itself is returned only once.
+func CreateAPIKey(c *gin.Context) {
+	var req dtos.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	scopes, ok := normalizeAPIKeyScopes(req.Scopes)
+	if !ok {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Unknown scope, allowed scopes are: "+strings.Join(models.APIKeyScopes, ", ")))
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("expires_at must be in the future"))
+		return
+	}
+
+	// Keys act as a user, so the owning account must exist and be active
+	userID := req.UserID
+	if userID == 0 {
+		userID = c.GetUint("user_id")
+	}
+	// A key acts as its owner, so only admins may issue keys for someone else
+	if userID != c.GetUint("user_id") && !callerIsAdmin(c) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Admin access required to create API keys for other users"))
+		return
+	}
+	var owner models.User
+	if err := database.DB.Where("id = ? AND is_active = ?", userID, true).First(&owner).Error; err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("User not found or inactive"))
+		return
+	}
+
+	plainKey, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to generate API key"))
+		return
+	}
+
+	rateLimit := defaultAPIKeyRateLimit
+	if req.RateLimitPerMinute != nil {
+		rateLimit = *req.RateLimitPerMinute
+	}
+
+	key := models.APIKey{
+		Name:               req.Name,
+		Prefix:             prefix,
+		KeyHash:            utils.HashAPIKey(plainKey),
+		UserID:             owner.ID,
+		Scopes:             strings.Join(scopes, ","),
+		RateLimitPerMinute: rateLimit,
+		ExpiresAt:          req.ExpiresAt,
+		CreatedBy:          c.GetUint("user_id"),
+	}
+
+	if err := database.DB.Create(&key).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create API key"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventAPIKeyCreated, &owner.ID, owner.Email,
+		fmt.Sprintf("%s (%s) %s, owner %d, created by %d", key.Name, key.Prefix, key.Scopes, key.UserID, key.CreatedBy))
+
+	response := apiKeyResponse(key)
+	response["key"] = plainKey
+	c.JSON(http.StatusCreated, utils.SuccessResponse("API key created successfully. Store the key now, it won't be shown again.", gin.H{
+		"api_key": response,
+	}))
+}
+
+// GetAPIKeys - List API keys
+func GetAPIKeys(c *gin.Context) {
+	var keys []models.APIKey
+
+	// Add pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := database.DB.Model(&models.APIKey{})
+
+	// Only admins see other users' keys
+	if !callerIsAdmin(c) {
+		query = query.Where("user_id = ?", c.GetUint("user_id"))
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if search := c.Query("search"); search != "" {
+		query = query.Where("name ILIKE ? OR prefix ILIKE ?", "%"+search+"%", search+"%")
+	}
+	if c.Query("include_revoked") != "true" {
+		query = query.Where("revoked_at IS NULL")
+	}
+
+	// Get total count
+	var total int64
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch API keys"))
+		return
+	}
+
+	keyList := make([]gin.H, 0, len(keys))
+	for _, key := range keys {
+		keyList = append(keyList, apiKeyResponse(key))
+	}
+
+	c.JSON(http.StatusOK, utils.PaginationResponse("API keys retrieved successfully", keyList, page, limit, total))
+}
+
+// GetAPIKeyByID - Get an API key
+func GetAPIKeyByID(c *gin.Context) {
+	key, ok := loadAPIKey(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("API key retrieved successfully", gin.H{
+		"api_key": apiKeyResponse(key),
+	}))
+}
+
+// UpdateAPIKey - Change an API key's name, scopes, rate limit or expiry
+func UpdateAPIKey(c *gin.Context) {
+	var req dtos.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	key, ok := loadAPIKey(c)
+	if !ok {
+		return
+	}
+	if key.RevokedAt != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("API key has been revoked"))
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Scopes != nil {
+		scopes, ok := normalizeAPIKeyScopes(req.Scopes)
+		if !ok {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Unknown scope, allowed scopes are: "+strings.Join(models.APIKeyScopes, ", ")))
+			return
+		}
+		updates["scopes"] = strings.Join(scopes, ",")
+	}
+	if req.RateLimitPerMinute != nil {
+		updates["rate_limit_per_minute"] = *req.RateLimitPerMinute
+	}
+	if req.ClearExpiry {
+		updates["expires_at"] = nil
+	} else if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("expires_at must be in the future"))
+			return
+		}
+		updates["expires_at"] = *req.ExpiresAt
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(&key).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update API key"))
+			return
+		}
+	}
+
+	database.DB.First(&key, key.ID)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("API key updated successfully", gin.H{
+		"api_key": apiKeyResponse(key),
+	}))
+}
+
+// RevokeAPIKey - Revoke an API key. The record is kept for auditing.
+func RevokeAPIKey(c *gin.Context) {
+	key, ok := loadAPIKey(c)
+	if !ok {
+		return
+	}
+	if key.RevokedAt != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("API key is already revoked"))
+		return
+	}
+
+	if err := database.DB.Model(&key).Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to revoke API key"))
+		return
+	}
+
+	var owner models.User
+	database.DB.Select("id", "email").First(&owner, key.UserID)
+	recordSecurityEvent(c, models.EventAPIKeyRevoked, &key.UserID, owner.Email, key.Name+" ("+key.Prefix+")")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("API key revoked successfully", nil))
+}
+
+// loadAPIKey finds the API key named by the :id parameter, writing the error response itself
+func loadAPIKey(c *gin.Context) (models.APIKey, bool) {
+	var key models.APIKey
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid API key ID"))
+		return key, false
+	}
+
+	if err := database.DB.First(&key, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("API key not found"))
+			return key, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return key, false
+	}
+
+	if key.UserID != c.GetUint("user_id") && !callerIsAdmin(c) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Admin access required"))
+		return key, false
+	}
+
+	return key, true
+}
+
+// callerIsAdmin reports whether the signed-in user has the admin role
+func callerIsAdmin(c *gin.Context) bool {
+	user, ok := c.Get("user")
+	if !ok {
+		return false
+	}
+	caller, ok := user.(models.User)
+	return ok && caller.Role.Name == "admin"
+}
+
+// normalizeAPIKeyScopes validates scopes against the known list and drops duplicates
+func normalizeAPIKeyScopes(requested []string) ([]string, bool) {
+	var scopes []string
+	seen := map[string]bool{}
+	for _, scope := range requested {
+		scope = strings.TrimSpace(scope)
+		known := false
+		for _, allowed := range models.APIKeyScopes {
+			if scope == allowed {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, false
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, len(scopes) > 0
+}
+
+func apiKeyResponse(key models.APIKey) gin.H {
+	status := "active"
+	if key.RevokedAt != nil {
+		status = "revoked"
+	} else if !key.IsUsable(time.Now()) {
+		status = "expired"
+	}
+
+	return gin.H{
+		"id":                    key.ID,
+		"name":                  key.Name,
+		"prefix":                key.Prefix,
+		"user_id":               key.UserID,
+		"scopes":                key.ScopeList(),
+		"rate_limit_per_minute": key.RateLimitPerMinute,
+		"status":                status,
+		"expires_at":            key.ExpiresAt,
+		"last_used_at":          key.LastUsedAt,
+		"last_used_ip":          key.LastUsedIP,
+		"revoked_at":            key.RevokedAt,
+		"created_by":            key.CreatedBy,
+		"created_at":            key.CreatedAt,
+		"updated_at":            key.UpdatedAt,
+	}
+}
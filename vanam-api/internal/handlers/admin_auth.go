@@ -1,17 +1,22 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
 	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
 	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
 	"github.com/prabalesh/vanam/vanam-api/internal/utils"
-	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+	"gorm.io/gorm"
 )
 
 func AdminLogin(c *gin.Context) {
@@ -21,6 +26,14 @@ func AdminLogin(c *gin.Context) {
 		return
 	}
 
+	ip := c.ClientIP()
+
+	// Refuse attempts while the account is locked, the IP is blocked or a delay is pending
+	if check := security.CheckLogin(req.Email, ip); check.Block != security.LoginAllowed {
+		rejectLogin(c, req.Email, check)
+		return
+	}
+
 	// find admin role
 	var adminRole models.Role
 	if err := database.DB.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
@@ -32,29 +45,56 @@ func AdminLogin(c *gin.Context) {
 	// find admin user
 	var user models.User
 	if err := database.DB.Preload("Role").Where("email = ? AND role_id = ? AND is_active = ?", req.Email, adminRole.ID, true).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid admin credentials"))
+		failLogin(c, req.Email, nil)
 		return
 	}
 
 	// Check password
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid admin credentials"))
+		failLogin(c, req.Email, &user.ID)
+		return
+	}
+
+	beginSession(c, user)
+}
+
+// beginSession finishes a first factor login. Staff with 2FA only get a short-lived
+// challenge until the code is verified.
+func beginSession(c *gin.Context, user models.User) {
+	if user.TOTPEnabled {
+		challenge, err := sessions.CreateChallenge(user.ID, user.Email, c.ClientIP(), c.GetHeader("User-Agent"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to start two-factor verification"))
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse("Two-factor verification required", gin.H{
+			"two_factor_required": true,
+			"challenge_token":     challenge.Token,
+			"expires_at":          challenge.ExpiresAt,
+		}))
 		return
 	}
 
+	completeLogin(c, user)
+}
+
+// completeLogin opens a session for a fully authenticated user and writes the login response
+func completeLogin(c *gin.Context, user models.User) {
+	security.RecordLoginSuccess(user.Email)
+	recordSecurityEvent(c, models.EventLoginSucceeded, &user.ID, user.Email, "")
+
 	// Create admin session
 	session := models.Session{
 		SessionID: utils.GenerateSecureToken(),
 		UserID:    user.ID,
 		RoleID:    user.RoleID,
 		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(8 * time.Hour),
 		IPAddress: c.ClientIP(),
 		UserAgent: c.GetHeader("User-Agent"),
 	}
 
-	sessionJSON, _ := json.Marshal(session)
-	if err := redis.Client.Set(redis.Ctx, "session:"+session.SessionID, sessionJSON, 8*time.Hour).Err(); err != nil {
+	if err := sessions.Create(&session, 8*time.Hour); err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create admin session"))
 		return
 	}
@@ -67,26 +107,139 @@ func AdminLogin(c *gin.Context) {
 			"name":  user.Name,
 			"role":  user.Role,
 		},
+		"password_change_required":       user.MustChangePassword || security.PasswordExpired(user),
+		"password_expires_at":            security.PasswordExpiresAt(user),
+		"two_factor_enrollment_required": user.Role.RequireTwoFactor && !user.TOTPEnabled,
 	}))
 }
 
-func AdminLogout(c *gin.Context) {
-	// Get session token from header or cookie
-	token := c.GetHeader("Authorization")
-	if token == "" {
-		token = c.GetHeader("X-Session-Token")
+// ChangePassword - Change the logged-in user's password and end their other sessions
+func ChangePassword(c *gin.Context) {
+	var req dtos.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, c.GetUint("user_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("User not found"))
+		return
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, user.Password) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Current password is incorrect"))
+		return
+	}
+
+	if req.NewPassword == req.CurrentPassword {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("New password must be different from the current one"))
+		return
+	}
+
+	if rejectWeakPassword(c, "new_password", req.NewPassword, user) {
+		return
 	}
 
-	if token == "" {
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to hash password"))
+		return
+	}
+
+	if err := setPassword(user, hashedPassword, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to change password"))
+		return
+	}
+
+	revoked, _ := sessions.RevokeAll(user.ID, c.GetString("session_id"))
+	recordSecurityEvent(c, models.EventPasswordChanged, &user.ID, user.Email, "")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Password changed successfully", gin.H{
+		"sessions_revoked": revoked,
+	}))
+}
+
+// setPassword stores a new password hash along with any other updates, clears a forced
+// change and keeps the old hash in the user's password history
+func setPassword(user models.User, hashedPassword string, updates map[string]interface{}) error {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["password"] = hashedPassword
+	updates["must_change_password"] = false
+	updates["password_changed_at"] = time.Now()
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return security.RememberPassword(tx, user.ID, user.Password)
+	})
+}
+
+// failLogin counts a failed attempt, audits it and writes the response
+func failLogin(c *gin.Context, email string, userID *uint) {
+	countLoginFailure(c, models.EventLoginFailed, email, userID)
+	c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid admin credentials"))
+}
+
+// countLoginFailure feeds a failed password or second factor into the brute-force counters
+func countLoginFailure(c *gin.Context, eventType models.SecurityEventType, email string, userID *uint) {
+	failure, err := security.RecordLoginFailure(email, c.ClientIP())
+	if err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+	}
+
+	recordSecurityEvent(c, eventType, userID, email, fmt.Sprintf("attempt %d", failure.Attempts))
+	if failure.Locked {
+		recordSecurityEvent(c, models.EventAccountLocked, userID, email,
+			fmt.Sprintf("locked for %d minutes after %d failed attempts", config.App.LoginLockoutMinutes, failure.Attempts))
+	}
+	if failure.IPBlocked {
+		recordSecurityEvent(c, models.EventIPBlocked, nil, email,
+			fmt.Sprintf("blocked for %d minutes after %d failed attempts", config.App.LoginLockoutMinutes, failure.IPAttempts))
+	}
+
+	if failure.Delay > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(failure.Delay.Seconds()))))
+	}
+}
+
+// rejectLogin answers an attempt that was blocked before the password was checked
+func rejectLogin(c *gin.Context, email string, check security.LoginCheck) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(check.RetryAfter.Seconds()))))
+
+	switch check.Block {
+	case security.LoginLocked:
+		recordSecurityEvent(c, models.EventLoginThrottled, nil, email, "account locked")
+		c.JSON(http.StatusLocked, utils.ErrorResponse("Account is temporarily locked after too many failed attempts"))
+	case security.LoginIPBlocked:
+		recordSecurityEvent(c, models.EventLoginThrottled, nil, email, "ip blocked")
+		c.JSON(http.StatusTooManyRequests, utils.ErrorResponse("Too many failed login attempts from this address"))
+	default:
+		c.JSON(http.StatusTooManyRequests, utils.ErrorResponse("Please wait before trying again"))
+	}
+}
+
+func AdminLogout(c *gin.Context) {
+	// Session is resolved by the auth middleware
+	sessionID := c.GetString("session_id")
+	if sessionID == "" {
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Session token required"))
 		return
 	}
 
-	// Remove session from Redis
-	if err := redis.Client.Del(redis.Ctx, "session:"+token).Err(); err != nil {
+	// Remove session and its index entry from Redis
+	if err := sessions.DeleteByID(sessionID); err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to logout"))
 		return
 	}
 
+	if c.GetUint("impersonator_id") != 0 {
+		user := c.MustGet("user").(models.User)
+		recordSecurityEvent(c, models.EventImpersonationEnded, &user.ID, user.Email, "")
+	}
+
 	c.JSON(http.StatusOK, utils.SuccessResponse("Logout successful", nil))
 }
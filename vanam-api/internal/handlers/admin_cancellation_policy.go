@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetCancellationPolicies - List cancellation policies (admin only)
+func GetCancellationPolicies(c *gin.Context) {
+	var policies []models.CancellationPolicy
+	query := database.DB.Preload("Tiers")
+
+	if theaterID := c.Query("theater_id"); theaterID != "" {
+		query = query.Where("theater_id = ?", theaterID)
+	}
+	if screeningID := c.Query("screening_id"); screeningID != "" {
+		query = query.Where("screening_id = ?", screeningID)
+	}
+
+	if err := query.Order("id ASC").Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch cancellation policies"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Cancellation policies retrieved successfully", policies))
+}
+
+// CreateCancellationPolicy - Create a policy for the default scope, a theater or a screening
+func CreateCancellationPolicy(c *gin.Context) {
+	var req dtos.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	if msg := validateCancellationPolicyScope(req); msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+
+	policy := models.CancellationPolicy{IsActive: true}
+	applyCancellationPolicyRequest(&policy, req)
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if policy.IsDefault {
+			if err := tx.Model(&models.CancellationPolicy{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&policy).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create cancellation policy"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Cancellation policy created successfully", policy))
+}
+
+// UpdateCancellationPolicy - Replace a policy's settings and tiers
+func UpdateCancellationPolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	id, err := strconv.ParseUint(policyID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid policy ID"))
+		return
+	}
+
+	var req dtos.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	if msg := validateCancellationPolicyScope(req); msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+
+	var policy models.CancellationPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Cancellation policy not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	applyCancellationPolicyRequest(&policy, req)
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if policy.IsDefault {
+			if err := tx.Model(&models.CancellationPolicy{}).Where("is_default = ? AND id <> ?", true, policy.ID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&models.CancellationTier{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(&policy).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update cancellation policy"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Cancellation policy updated successfully", policy))
+}
+
+// DeleteCancellationPolicy - Delete a policy and its tiers
+func DeleteCancellationPolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	id, err := strconv.ParseUint(policyID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid policy ID"))
+		return
+	}
+
+	var policy models.CancellationPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Cancellation policy not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&models.CancellationTier{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&policy).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete cancellation policy"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Cancellation policy deleted successfully", nil))
+}
+
+// validateCancellationPolicyScope makes sure a policy targets exactly one scope
+func validateCancellationPolicyScope(req dtos.CancellationPolicyRequest) string {
+	scopes := 0
+	if req.IsDefault {
+		scopes++
+	}
+	if req.TheaterID != nil {
+		scopes++
+		var theater models.Theater
+		if err := database.DB.First(&theater, *req.TheaterID).Error; err != nil {
+			return "Invalid theater ID"
+		}
+	}
+	if req.ScreeningID != nil {
+		scopes++
+		var screening models.Screening
+		if err := database.DB.First(&screening, *req.ScreeningID).Error; err != nil {
+			return "Invalid screening ID"
+		}
+	}
+
+	if scopes != 1 {
+		return "Policy must be either the default or apply to one theater or one screening"
+	}
+
+	seen := make(map[int]bool)
+	for _, tier := range req.Tiers {
+		if seen[tier.HoursBefore] {
+			return "Tiers must have distinct hours_before values"
+		}
+		seen[tier.HoursBefore] = true
+	}
+
+	return ""
+}
+
+func applyCancellationPolicyRequest(policy *models.CancellationPolicy, req dtos.CancellationPolicyRequest) {
+	policy.Name = req.Name
+	policy.TheaterID = req.TheaterID
+	policy.ScreeningID = req.ScreeningID
+	policy.IsDefault = req.IsDefault
+	policy.CutoffMinutes = req.CutoffMinutes
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	policy.Tiers = nil
+	for _, tier := range req.Tiers {
+		policy.Tiers = append(policy.Tiers, models.CancellationTier{
+			PolicyID:      policy.ID,
+			HoursBefore:   tier.HoursBefore,
+			RefundPercent: tier.RefundPercent,
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/catalog"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/i18n"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxCatalogImportBytes = 10 << 20
+
+// ImportCatalog - Import a CSV or JSON bundle of movies with genres, cast and localized
+// titles. Movies are matched on external_id, so importing a bundle again only updates
+// what changed. With dry_run=true nothing is written. A bundle with invalid rows is
+// rejected unless skip_invalid=true, which imports the valid rows.
+func ImportCatalog(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("file is required"))
+		return
+	}
+	if fileHeader.Size > maxCatalogImportBytes {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("File is too large"))
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		}
+	}
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("format must be csv or json"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read file"))
+		return
+	}
+	defer file.Close()
+
+	var entries []catalog.Entry
+	var issues []catalog.Issue
+	if format == "json" {
+		entries, err = catalog.ReadJSON(file)
+	} else {
+		entries, issues, err = catalog.ReadCSV(file)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if len(entries) == 0 && len(issues) == 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("File has no movies"))
+		return
+	}
+
+	plan, err := planCatalogImport(entries, issues)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to check catalog import"))
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		c.JSON(http.StatusOK, utils.SuccessResponse("Catalog import preview", plan.report))
+		return
+	}
+
+	if plan.report.Failed > 0 && c.Query("skip_invalid") != "true" {
+		response := utils.ErrorResponse("Catalog import has invalid rows, nothing was imported")
+		response["data"] = plan.report
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	plan.apply()
+	plan.report.Applied = true
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Catalog imported successfully", plan.report))
+}
+
+// catalogRow is a checked bundle entry and the state it would leave its movie in
+type catalogRow struct {
+	result        *dtos.CatalogImportRow
+	movie         models.Movie
+	genres        []models.Genre
+	cast          []string // nil leaves the cast as it is
+	localizations []models.MovieLanguage
+}
+
+type catalogImport struct {
+	report dtos.CatalogImportReport
+	rows   []catalogRow
+	people map[string]models.Person // By lower case name
+}
+
+// planCatalogImport checks every entry against the catalog and works out what importing
+// it would change
+func planCatalogImport(entries []catalog.Entry, issues []catalog.Issue) (*catalogImport, error) {
+	plan := &catalogImport{people: make(map[string]models.Person)}
+
+	var genres []models.Genre
+	if err := database.DB.Find(&genres).Error; err != nil {
+		return nil, err
+	}
+	genresByName := make(map[string]models.Genre)
+	for _, genre := range genres {
+		genresByName[strings.ToLower(genre.Name)] = genre
+	}
+
+	var languages []models.Language
+	if err := database.DB.Find(&languages).Error; err != nil {
+		return nil, err
+	}
+	languagesByCode := make(map[string]models.Language)
+	for _, language := range languages {
+		languagesByCode[i18n.Normalize(language.Code)] = language
+	}
+
+	var externalIDs, castNames []string
+	for _, entry := range entries {
+		externalIDs = append(externalIDs, strings.TrimSpace(entry.ExternalID))
+		for _, name := range entry.Cast {
+			castNames = append(castNames, strings.ToLower(strings.TrimSpace(name)))
+		}
+	}
+
+	// Deleted movies are looked up too, as they still hold their external ID
+	var existing []models.Movie
+	if err := preloadCredits(database.DB.Unscoped()).Preload("Genres").Preload("MovieLanguages").
+		Where("external_id IN ?", externalIDs).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	moviesByExternalID := make(map[string]models.Movie)
+	for _, movie := range existing {
+		moviesByExternalID[*movie.ExternalID] = movie
+	}
+
+	if len(castNames) > 0 {
+		var people []models.Person
+		if err := database.DB.Where("LOWER(name) IN ?", castNames).Order("id ASC").Find(&people).Error; err != nil {
+			return nil, err
+		}
+		for _, person := range people {
+			if _, ok := plan.people[strings.ToLower(person.Name)]; !ok {
+				plan.people[strings.ToLower(person.Name)] = person
+			}
+		}
+	}
+
+	ratings, err := ratingCodes(database.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		plan.report.Results = append(plan.report.Results, dtos.CatalogImportRow{Row: issue.Row, Action: "error", Errors: []string{issue.Message}})
+	}
+
+	duplicates := make(map[int]string)
+	for _, issue := range catalog.DuplicateExternalIDs(entries) {
+		duplicates[issue.Row] = issue.Message
+	}
+
+	for _, entry := range entries {
+		entry.ExternalID = strings.TrimSpace(entry.ExternalID)
+		result := dtos.CatalogImportRow{Row: entry.Row, ExternalID: entry.ExternalID, Title: entry.OriginalTitle}
+
+		problems := catalog.Validate(entry, ratings)
+		if duplicate, ok := duplicates[entry.Row]; ok {
+			problems = append(problems, duplicate)
+		}
+
+		var row catalogRow
+		if len(problems) == 0 {
+			row, problems = plan.planEntry(entry, &result, moviesByExternalID, genresByName, languagesByCode)
+		}
+
+		if len(problems) > 0 {
+			result.Action = "error"
+			result.Errors = problems
+		}
+		plan.report.Results = append(plan.report.Results, result)
+		if result.Action != "error" {
+			plan.rows = append(plan.rows, row)
+		}
+	}
+
+	sort.SliceStable(plan.report.Results, func(i, j int) bool {
+		return plan.report.Results[i].Row < plan.report.Results[j].Row
+	})
+	// Point rows at their results in the finished report, so applying them updates it
+	resultsByRow := make(map[int]*dtos.CatalogImportRow)
+	for i := range plan.report.Results {
+		resultsByRow[plan.report.Results[i].Row] = &plan.report.Results[i]
+	}
+	for i := range plan.rows {
+		plan.rows[i].result = resultsByRow[plan.rows[i].result.Row]
+	}
+
+	plan.count()
+	return plan, nil
+}
+
+// planEntry resolves an entry's genres and languages and compares it with the movie
+// already imported under its external ID, if any
+func (plan *catalogImport) planEntry(entry catalog.Entry, result *dtos.CatalogImportRow, moviesByExternalID map[string]models.Movie,
+	genresByName map[string]models.Genre, languagesByCode map[string]models.Language) (catalogRow, []string) {
+	var problems []string
+	row := catalogRow{result: result}
+
+	for _, name := range entry.Genres {
+		genre, ok := genresByName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown genre %q", name))
+			continue
+		}
+		row.genres = append(row.genres, genre)
+	}
+
+	for _, localization := range entry.Localizations {
+		language, ok := languagesByCode[i18n.Normalize(localization.Language)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown language %q", localization.Language))
+			continue
+		}
+		row.localizations = append(row.localizations, models.MovieLanguage{
+			LanguageID:  language.ID,
+			Title:       strings.TrimSpace(localization.Title),
+			Description: strings.TrimSpace(localization.Description),
+		})
+	}
+
+	existing, found := moviesByExternalID[entry.ExternalID]
+	if found && existing.DeletedAt.Valid {
+		problems = append(problems, fmt.Sprintf("external_id belongs to deleted movie %d", existing.ID))
+	}
+
+	releaseDate, _ := catalog.ParseDate(entry.ReleaseDate)
+	comingSoonAt, bookingOpensAt := defaultLifecycleDates(releaseDate)
+	if found {
+		// Like UpdateMovie, existing lifecycle dates move with the release date
+		shift := releaseDate.Sub(existing.ReleaseDate)
+		if existing.ComingSoonAt != nil {
+			comingSoonAt = existing.ComingSoonAt.Add(shift)
+		}
+		if existing.BookingOpensAt != nil {
+			bookingOpensAt = existing.BookingOpensAt.Add(shift)
+		}
+	}
+	if entry.ComingSoonAt != "" {
+		comingSoonAt, _ = catalog.ParseDate(entry.ComingSoonAt)
+	}
+	if entry.BookingOpensAt != "" {
+		bookingOpensAt, _ = catalog.ParseDate(entry.BookingOpensAt)
+	}
+	if msg := validateLifecycleDates(comingSoonAt, bookingOpensAt, releaseDate); msg != "" {
+		problems = append(problems, msg)
+	}
+
+	if len(problems) > 0 {
+		return row, problems
+	}
+
+	externalID := entry.ExternalID
+	movie := existing
+	if !found {
+		movie = models.Movie{IsActive: true, ExternalID: &externalID}
+	}
+	movie.OriginalTitle = strings.TrimSpace(entry.OriginalTitle)
+	movie.Duration = entry.DurationMinutes
+	movie.ReleaseDate = releaseDate
+	movie.Rating = models.MovieRating(entry.Rating)
+	movie.ComingSoonAt = &comingSoonAt
+	movie.BookingOpensAt = &bookingOpensAt
+	// Blank optional fields leave what an admin may have filled in since
+	if entry.Description != "" {
+		movie.Description = strings.TrimSpace(entry.Description)
+	}
+	if entry.PosterURL != "" {
+		movie.PosterURL = strings.TrimSpace(entry.PosterURL)
+	}
+	row.movie = movie
+
+	// An empty list clears the cast, which the nil row.cast would leave as it is
+	if entry.Cast != nil {
+		row.cast = make([]string, 0, len(entry.Cast))
+		for _, name := range entry.Cast {
+			row.cast = append(row.cast, strings.TrimSpace(name))
+		}
+	}
+
+	for _, name := range row.cast {
+		if _, ok := plan.people[strings.ToLower(name)]; !ok {
+			result.NewPeople = append(result.NewPeople, name)
+		}
+	}
+
+	if !found {
+		result.Action = "create"
+		return row, nil
+	}
+
+	result.MovieID = existing.ID
+	result.Changes = movieChanges(existing, row)
+	result.Action = "update"
+	if len(result.Changes) == 0 {
+		result.Action = "unchanged"
+	}
+	return row, nil
+}
+
+// movieChanges lists the fields importing row would change on movie
+func movieChanges(movie models.Movie, row catalogRow) []string {
+	var changes []string
+	changed := func(field string, different bool) {
+		if different {
+			changes = append(changes, field)
+		}
+	}
+	sameTime := func(a, b *time.Time) bool {
+		return a != nil && b != nil && a.Equal(*b)
+	}
+
+	changed("original_title", movie.OriginalTitle != row.movie.OriginalTitle)
+	changed("duration_minutes", movie.Duration != row.movie.Duration)
+	changed("release_date", !movie.ReleaseDate.Equal(row.movie.ReleaseDate))
+	changed("rating", movie.Rating != row.movie.Rating)
+	changed("description", movie.Description != row.movie.Description)
+	changed("poster_url", movie.PosterURL != row.movie.PosterURL)
+	changed("coming_soon_at", !sameTime(movie.ComingSoonAt, row.movie.ComingSoonAt))
+	changed("booking_opens_at", !sameTime(movie.BookingOpensAt, row.movie.BookingOpensAt))
+
+	genreIDs := make([]string, len(row.genres))
+	for i, genre := range row.genres {
+		genreIDs[i] = fmt.Sprint(genre.ID)
+	}
+	currentGenreIDs := make([]string, len(movie.Genres))
+	for i, genre := range movie.Genres {
+		currentGenreIDs[i] = fmt.Sprint(genre.ID)
+	}
+	changed("genres", !sameSet(genreIDs, currentGenreIDs))
+
+	if row.cast != nil {
+		var names, currentNames []string
+		for _, name := range row.cast {
+			names = append(names, strings.ToLower(name))
+		}
+		for _, credit := range movie.MovieCasts {
+			if credit.Role == models.RoleActor {
+				currentNames = append(currentNames, strings.ToLower(credit.Person.Name))
+			}
+		}
+		// Billing follows the order of the list, so reordering is a change too
+		changed("cast", strings.Join(names, "\n") != strings.Join(currentNames, "\n"))
+	}
+
+	current := make(map[uint]models.MovieLanguage)
+	for _, ml := range movie.MovieLanguages {
+		current[ml.LanguageID] = ml
+	}
+	for _, localization := range row.localizations {
+		ml, ok := current[localization.LanguageID]
+		if !ok || ml.Title != localization.Title || (localization.Description != "" && ml.Description != localization.Description) {
+			changes = append(changes, "localizations")
+			break
+		}
+	}
+
+	return changes
+}
+
+func sameSet(a, b []string) bool {
+	set := make(map[string]bool)
+	for _, item := range a {
+		set[item] = true
+	}
+	other := make(map[string]bool)
+	for _, item := range b {
+		if !set[item] {
+			return false
+		}
+		other[item] = true
+	}
+	return len(set) == len(other)
+}
+
+// apply imports each planned row in its own transaction, so one failing row doesn't undo
+// the others. Rows that fail are reported as errors.
+func (plan *catalogImport) apply() {
+	for i := range plan.rows {
+		row := &plan.rows[i]
+		if row.result.Action == "unchanged" {
+			continue
+		}
+
+		created := make(map[string]models.Person)
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return plan.applyRow(tx, row, created)
+		})
+		if err != nil {
+			log.Printf("❌ Catalog import of %s failed: %v", row.result.ExternalID, err)
+			row.result.Action = "error"
+			row.result.Errors = append(row.result.Errors, "Failed to import movie")
+			continue
+		}
+
+		for name, person := range created {
+			plan.people[name] = person
+		}
+		row.result.MovieID = row.movie.ID
+	}
+
+	plan.count()
+}
+
+func (plan *catalogImport) applyRow(tx *gorm.DB, row *catalogRow, created map[string]models.Person) error {
+	movie := &row.movie
+	if movie.ID == 0 {
+		movie.Status = movie.StatusAt(time.Now(), nil)
+		if err := tx.Omit(clause.Associations).Create(movie).Error; err != nil {
+			return err
+		}
+	} else if err := tx.Omit(clause.Associations).Save(movie).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(movie).Association("Genres").Replace(row.genres); err != nil {
+		return err
+	}
+
+	if row.cast != nil {
+		var cast []uint
+		for _, name := range row.cast {
+			key := strings.ToLower(name)
+			person, ok := plan.people[key]
+			if !ok {
+				if person, ok = created[key]; !ok {
+					person = models.Person{Name: name}
+					if err := tx.Create(&person).Error; err != nil {
+						return err
+					}
+					created[key] = person
+				}
+			}
+			cast = append(cast, person.ID)
+		}
+		if err := replaceActors(tx, movie.ID, cast); err != nil {
+			return err
+		}
+	}
+
+	current := make(map[uint]models.MovieLanguage)
+	for _, ml := range movie.MovieLanguages {
+		current[ml.LanguageID] = ml
+	}
+	for _, localization := range row.localizations {
+		ml, ok := current[localization.LanguageID]
+		if !ok {
+			ml = models.MovieLanguage{MovieID: movie.ID, LanguageID: localization.LanguageID}
+		}
+		ml.Title = localization.Title
+		if localization.Description != "" {
+			ml.Description = localization.Description
+		}
+		if err := tx.Omit(clause.Associations).Save(&ml).Error; err != nil {
+			return err
+		}
+	}
+
+	return refreshMovieStatus(tx, movie.ID)
+}
+
+func (plan *catalogImport) count() {
+	report := &plan.report
+	report.Rows = len(report.Results)
+	report.Created, report.Updated, report.Unchanged, report.Failed = 0, 0, 0, 0
+	for _, result := range report.Results {
+		switch result.Action {
+		case "create":
+			report.Created++
+		case "update":
+			report.Updated++
+		case "unchanged":
+			report.Unchanged++
+		default:
+			report.Failed++
+		}
+	}
+}
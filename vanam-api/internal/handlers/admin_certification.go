@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetCertificationSystems - Get certification systems with their categories, optionally ?country=IN (public endpoint)
+func GetCertificationSystems(c *gin.Context) {
+	query := database.DB.Preload("Certifications", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, id ASC")
+	})
+	if country := c.Query("country"); country != "" {
+		query = query.Where("country = ?", strings.ToUpper(country))
+	}
+	if c.Query("all") != "true" {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var systems []models.CertificationSystem
+	if err := query.Order("country ASC").Find(&systems).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch certification systems"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Certification systems retrieved successfully", systems))
+}
+
+// CreateCertificationSystem - Add a country's certification system (admin only)
+func CreateCertificationSystem(c *gin.Context) {
+	var req dtos.CertificationSystemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	system := models.CertificationSystem{
+		Country:  strings.ToUpper(req.Country),
+		Name:     req.Name,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		system.IsActive = *req.IsActive
+	}
+
+	var existing models.CertificationSystem
+	if err := database.DB.Where("country = ?", system.Country).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Country already has a certification system"))
+		return
+	}
+
+	if err := database.DB.Create(&system).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create certification system"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Certification system created successfully", system))
+}
+
+// UpdateCertificationSystem - Rename or deactivate a certification system (admin only)
+func UpdateCertificationSystem(c *gin.Context) {
+	system, ok := loadCertificationSystem(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.CertificationSystemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if strings.ToUpper(req.Country) != system.Country {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("The country of a certification system can't be changed"))
+		return
+	}
+
+	system.Name = req.Name
+	if req.IsActive != nil {
+		system.IsActive = *req.IsActive
+	}
+
+	if err := database.DB.Save(&system).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update certification system"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Certification system updated successfully", system))
+}
+
+// CreateCertification - Add a category to a certification system (admin only)
+func CreateCertification(c *gin.Context) {
+	system, ok := loadCertificationSystem(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.CertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if req.Restricted && req.MinAge == 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Restricted certifications need a min_age"))
+		return
+	}
+
+	var existing models.Certification
+	if err := database.DB.Where("system_id = ? AND code = ?", system.ID, req.Code).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Certification system already has this code"))
+		return
+	}
+
+	certification := models.Certification{
+		SystemID:    system.ID,
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		MinAge:      req.MinAge,
+		Restricted:  req.Restricted,
+		SortOrder:   req.SortOrder,
+	}
+
+	if err := database.DB.Create(&certification).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create certification"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Certification created successfully", certification))
+}
+
+// UpdateCertification - Update a certification category (admin only)
+func UpdateCertification(c *gin.Context) {
+	certification, ok := loadCertification(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.CertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if req.Restricted && req.MinAge == 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Restricted certifications need a min_age"))
+		return
+	}
+
+	var existing models.Certification
+	if err := database.DB.Where("system_id = ? AND code = ? AND id != ?", certification.SystemID, req.Code, certification.ID).
+		First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Certification system already has this code"))
+		return
+	}
+
+	certification.Code = req.Code
+	certification.Name = req.Name
+	certification.Description = req.Description
+	certification.MinAge = req.MinAge
+	certification.Restricted = req.Restricted
+	certification.SortOrder = req.SortOrder
+
+	if err := database.DB.Save(&certification).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update certification"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Certification updated successfully", certification))
+}
+
+// DeleteCertification - Delete a certification category no movie carries (admin only)
+func DeleteCertification(c *gin.Context) {
+	certification, ok := loadCertification(c)
+	if !ok {
+		return
+	}
+
+	var used int64
+	database.DB.Model(&models.MovieCertification{}).Where("certification_id = ?", certification.ID).Count(&used)
+	if used > 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Certification is assigned to movies"))
+		return
+	}
+
+	if err := database.DB.Delete(&certification).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete certification"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Certification deleted successfully", nil))
+}
+
+// GetMovieCertifications - Get a movie's certifications in every region and its content advisories
+func GetMovieCertifications(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	var certifications []models.MovieCertification
+	database.DB.Preload("Certification").Preload("System").Where("movie_id = ?", movie.ID).Find(&certifications)
+
+	var advisories []models.ContentAdvisory
+	database.DB.Where("movie_id = ?", movie.ID).Order("id ASC").Find(&advisories)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Movie certifications retrieved successfully", gin.H{
+		"rating":         movie.Rating,
+		"certifications": certifications,
+		"advisories":     advisories,
+	}))
+}
+
+// SetMovieCertification - Record the certification a movie was given in a region,
+// replacing any earlier one from the same system (admin only)
+func SetMovieCertification(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.MovieCertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	var certification models.Certification
+	if err := database.DB.Preload("System").First(&certification, req.CertificationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Certification not found"))
+		return
+	}
+
+	var movieCertification models.MovieCertification
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("movie_id = ? AND system_id = ?", movie.ID, certification.SystemID).
+			FirstOrInit(&movieCertification).Error; err != nil {
+			return err
+		}
+		movieCertification.MovieID = movie.ID
+		movieCertification.SystemID = certification.SystemID
+		movieCertification.CertificationID = certification.ID
+		movieCertification.CertificateNumber = req.CertificateNumber
+		if err := tx.Omit("Certification", "System").Save(&movieCertification).Error; err != nil {
+			return err
+		}
+
+		// Keep the legacy rating in step with the default country's certification
+		if certification.System.Country == strings.ToUpper(config.App.DefaultCountry) {
+			return tx.Model(&movie).Update("rating", certification.Code).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save movie certification"))
+		return
+	}
+
+	movieCertification.Certification = certification
+	movieCertification.System = certification.System
+	movieCertification.Certification.System = nil
+	c.JSON(http.StatusOK, utils.SuccessResponse("Movie certification saved successfully", movieCertification))
+}
+
+// RemoveMovieCertification - Remove a movie's certification in one region (admin only)
+func RemoveMovieCertification(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
+		return
+	}
+
+	result := database.DB.Where("id = ? AND movie_id = ?", c.Param("certificationId"), movieID).Delete(&models.MovieCertification{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to remove movie certification"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Movie certification not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Movie certification removed successfully", nil))
+}
+
+// AddContentAdvisory - Add a content advisory to a movie (admin only)
+func AddContentAdvisory(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.ContentAdvisoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	var existing models.ContentAdvisory
+	if err := database.DB.Where("movie_id = ? AND category = ?", movie.ID, req.Category).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Movie already has an advisory in this category"))
+		return
+	}
+
+	advisory := models.ContentAdvisory{
+		MovieID:     movie.ID,
+		Category:    req.Category,
+		Severity:    req.Severity,
+		Description: req.Description,
+	}
+
+	if err := database.DB.Create(&advisory).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to add content advisory"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Content advisory added successfully", advisory))
+}
+
+// RemoveContentAdvisory - Remove a content advisory from a movie (admin only)
+func RemoveContentAdvisory(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
+		return
+	}
+
+	result := database.DB.Where("id = ? AND movie_id = ?", c.Param("advisoryId"), movieID).Delete(&models.ContentAdvisory{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to remove content advisory"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Content advisory not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Content advisory removed successfully", nil))
+}
+
+// loadCertificationSystem finds the certification system addressed by :id, responding if it can't
+func loadCertificationSystem(c *gin.Context) (models.CertificationSystem, bool) {
+	var system models.CertificationSystem
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid certification system ID"))
+		return system, false
+	}
+
+	if err := database.DB.First(&system, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Certification system not found"))
+			return system, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return system, false
+	}
+
+	return system, true
+}
+
+// loadCertification finds the certification addressed by :id, responding if it can't
+func loadCertification(c *gin.Context) (models.Certification, bool) {
+	var certification models.Certification
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid certification ID"))
+		return certification, false
+	}
+
+	if err := database.DB.First(&certification, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Certification not found"))
+			return certification, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return certification, false
+	}
+
+	return certification, true
+}
+
+// ratingCertifications loads the default country's certifications in order. Their codes
+// are the ratings a movie can be given. Before that country has a system, the CBFC codes
+// are used with no names.
+func ratingCertifications(db *gorm.DB) ([]models.Certification, error) {
+	var certifications []models.Certification
+	if err := db.Joins("JOIN certification_systems ON certification_systems.id = certifications.system_id").
+		Where("certification_systems.country = ?", strings.ToUpper(config.App.DefaultCountry)).
+		Order("certifications.sort_order ASC, certifications.id ASC").
+		Find(&certifications).Error; err != nil {
+		return nil, err
+	}
+	if len(certifications) == 0 {
+		for _, rating := range models.MovieRatings {
+			certifications = append(certifications, models.Certification{Code: string(rating)})
+		}
+	}
+	return certifications, nil
+}
+
+// ratingCodes lists the codes of the default country's certifications
+func ratingCodes(db *gorm.DB) ([]string, error) {
+	certifications, err := ratingCertifications(db)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, len(certifications))
+	for i, certification := range certifications {
+		codes[i] = certification.Code
+	}
+	return codes, nil
+}
+
+// checkRating responds with 400 when the rating isn't one of the default country's
+// certification codes
+func checkRating(c *gin.Context, rating string) bool {
+	codes, err := ratingCodes(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to validate rating"))
+		return false
+	}
+	if !slices.Contains(codes, rating) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("rating must be one of: "+strings.Join(codes, ", ")))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+	"gorm.io/gorm"
+)
+
+func attendanceKey(screeningID uint) string {
+	return fmt.Sprintf("attendance:screening:%d", screeningID)
+}
+
+// ScanTicket - Verify a ticket code at the door and admit it exactly once
+func ScanTicket(c *gin.Context) {
+	var req dtos.TicketScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	// Scanners often send the code with a trailing newline
+	req.Code = strings.TrimSpace(req.Code)
+
+	claims, err := utils.VerifyTicketCode(req.Code)
+	if err == utils.ErrExpiredTicketCode {
+		c.JSON(http.StatusGone, utils.ErrorResponse("Ticket code has expired"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ticket code"))
+		return
+	}
+
+	if claims.ScreeningID != req.ScreeningID {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Ticket is for a different screening"))
+		return
+	}
+
+	if req.SeatNumber != "" && req.SeatNumber != claims.SeatNumber {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Ticket is for seat "+claims.SeatNumber))
+		return
+	}
+
+	var ticket models.Ticket
+	if err := database.DB.First(&ticket, claims.TicketID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Ticket not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	// The signed payload must still describe the ticket on record
+	if ticket.ScreeningID != claims.ScreeningID || ticket.SeatID != claims.SeatID || ticket.Code != req.Code {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Ticket code has been superseded"))
+		return
+	}
+
+	switch ticket.Status {
+	case models.TicketCancelled:
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Ticket has been cancelled"))
+		return
+	case models.TicketAdmitted:
+		c.JSON(http.StatusConflict, gin.H{
+			"success":     false,
+			"message":     "Ticket already admitted",
+			"admitted_at": ticket.AdmittedAt,
+		})
+		return
+	}
+
+	// Conditional update so two scanners racing on the same ticket admit it only once
+	now := time.Now()
+	usherID := c.GetUint("user_id")
+	result := database.DB.Model(&models.Ticket{}).
+		Where("id = ? AND status = ?", ticket.ID, models.TicketIssued).
+		Updates(map[string]interface{}{
+			"status":      models.TicketAdmitted,
+			"admitted_at": now,
+			"admitted_by": usherID,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to admit ticket"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Ticket already admitted"))
+		return
+	}
+
+	admitted, err := redis.Client.Incr(redis.Ctx, attendanceKey(ticket.ScreeningID)).Result()
+	if err != nil || admitted == 1 {
+		// Counter was missing (or Redis failed), rebuild it from the database
+		admitted = countAdmitted(ticket.ScreeningID)
+		redis.Client.Set(redis.Ctx, attendanceKey(ticket.ScreeningID), admitted, 48*time.Hour)
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Ticket admitted", gin.H{
+		"ticket_id":    ticket.ID,
+		"booking_id":   ticket.BookingID,
+		"screening_id": ticket.ScreeningID,
+		"seat_number":  ticket.SeatNumber,
+		"admitted_at":  now,
+		"attendance":   admitted,
+	}))
+}
+
+// GetScreeningAttendance - Live check-in counts for a screening
+func GetScreeningAttendance(c *gin.Context) {
+	screeningID := c.Param("id")
+	id, err := strconv.ParseUint(screeningID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid screening ID"))
+		return
+	}
+
+	var screening models.Screening
+	if err := database.DB.Preload("Screen").First(&screening, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Screening not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	admitted, err := redis.Client.Get(redis.Ctx, attendanceKey(screening.ID)).Int64()
+	if err != nil {
+		admitted = countAdmitted(screening.ID)
+		redis.Client.Set(redis.Ctx, attendanceKey(screening.ID), admitted, 48*time.Hour)
+	}
+
+	var sold int64
+	database.DB.Model(&models.Ticket{}).
+		Where("screening_id = ? AND status <> ?", screening.ID, models.TicketCancelled).
+		Count(&sold)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Attendance retrieved successfully", gin.H{
+		"screening_id": screening.ID,
+		"capacity":     screening.Screen.Capacity,
+		"tickets_sold": sold,
+		"admitted":     admitted,
+		"not_yet_in":   sold - admitted,
+		"show_starts":  screening.StartsAt(),
+		"as_of":        time.Now(),
+	}))
+}
+
+// GetTicketPublicKey - Public key scanners use to verify ticket codes offline
+func GetTicketPublicKey(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse("Ticket public key retrieved successfully", gin.H{
+		"algorithm":  "Ed25519",
+		"public_key": base64.StdEncoding.EncodeToString(utils.TicketPublicKey()),
+	}))
+}
+
+func countAdmitted(screeningID uint) int64 {
+	var admitted int64
+	database.DB.Model(&models.Ticket{}).
+		Where("screening_id = ? AND status = ?", screeningID, models.TicketAdmitted).
+		Count(&admitted)
+	return admitted
+}
@@ -22,7 +22,8 @@ func GetAllGenres(c *gin.Context) {
 	// Optional search filter
 	query := database.DB.Model(&models.Genre{})
 	if search := c.Query("search"); search != "" {
-		query = query.Where("name ILIKE ?", "%"+search+"%")
+		pattern := "%" + search + "%"
+		query = query.Where("name ILIKE ? OR EXISTS (SELECT 1 FROM genre_languages WHERE genre_languages.genre_id = genres.id AND genre_languages.name ILIKE ?)", pattern, pattern)
 	}
 
 	if err := query.Order("name ASC").Find(&genres).Error; err != nil {
@@ -30,6 +31,8 @@ func GetAllGenres(c *gin.Context) {
 		return
 	}
 
+	localizeGenres(c, genres)
+
 	c.JSON(http.StatusOK, utils.SuccessResponse("Genres retrieved successfully", genres))
 }
 
@@ -130,7 +133,13 @@ func DeleteGenre(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&genre).Error; err != nil {
+	// Its translated names go with it
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("genre_id = ?", genre.ID).Delete(&models.GenreLanguage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&genre).Error
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete genre"))
 		return
 	}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// AddGenreLanguage - Add a translated name to a genre
+func AddGenreLanguage(c *gin.Context) {
+	genreID := c.Param("id")
+	id, err := strconv.Atoi(genreID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid genre ID"))
+		return
+	}
+
+	var req dtos.GenreLanguageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	// Check if genre exists
+	var genre models.Genre
+	if err := database.DB.First(&genre, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Genre not found"))
+		return
+	}
+
+	// Check if language exists
+	var language models.Language
+	if err := database.DB.First(&language, req.LanguageID).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Language not found"))
+		return
+	}
+
+	var existing models.GenreLanguage
+	if err := database.DB.Where("genre_id = ? AND language_id = ?", id, req.LanguageID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Genre already has a name in this language"))
+		return
+	}
+
+	genreLanguage := models.GenreLanguage{
+		GenreID:    uint(id),
+		LanguageID: req.LanguageID,
+		Name:       req.Name,
+	}
+
+	if err := database.DB.Create(&genreLanguage).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to add genre translation"))
+		return
+	}
+
+	genreLanguage.Language = language
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Genre translation added successfully", genreLanguage))
+}
+
+// GetGenreLanguages - Get all translated names of a genre
+func GetGenreLanguages(c *gin.Context) {
+	genreID := c.Param("id")
+	id, err := strconv.Atoi(genreID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid genre ID"))
+		return
+	}
+
+	var genreLanguages []models.GenreLanguage
+	if err := database.DB.Where("genre_id = ?", id).
+		Preload("Language").
+		Find(&genreLanguages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch genre translations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Genre translations retrieved successfully", genreLanguages))
+}
+
+// UpdateGenreLanguage - Update a genre's name in one language
+func UpdateGenreLanguage(c *gin.Context) {
+	genreLanguage, ok := loadGenreLanguage(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.GenreLanguageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	if err := database.DB.Model(&genreLanguage).Update("name", req.Name).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update genre translation"))
+		return
+	}
+
+	// Reload with language info
+	database.DB.Preload("Language").First(&genreLanguage, genreLanguage.ID)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Genre translation updated successfully", genreLanguage))
+}
+
+// RemoveGenreLanguage - Remove a genre's name in one language
+func RemoveGenreLanguage(c *gin.Context) {
+	genreLanguage, ok := loadGenreLanguage(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(&genreLanguage).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to remove genre translation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Genre translation removed successfully", nil))
+}
+
+// loadGenreLanguage finds the translation addressed by :id and :langId, responding if it can't
+func loadGenreLanguage(c *gin.Context) (models.GenreLanguage, bool) {
+	var genreLanguage models.GenreLanguage
+
+	genreID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid genre ID"))
+		return genreLanguage, false
+	}
+
+	langID, err := strconv.Atoi(c.Param("langId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid language ID"))
+		return genreLanguage, false
+	}
+
+	if err := database.DB.Where("genre_id = ? AND language_id = ?", genreID, langID).
+		First(&genreLanguage).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Genre translation not found"))
+			return genreLanguage, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return genreLanguage, false
+	}
+
+	return genreLanguage, true
+}
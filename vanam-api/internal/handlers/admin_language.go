@@ -123,15 +123,26 @@ func DeleteLanguage(c *gin.Context) {
 	}
 
 	// Check if language is used by any movies or screenings
-	var movieLanguageCount, screeningCount int64
+	var movieLanguageCount, originalLanguageCount, screeningCount int64
 	database.DB.Model(&models.MovieLanguage{}).Where("language_id = ?", id).Count(&movieLanguageCount)
+	database.DB.Model(&models.Movie{}).Where("original_language_id = ?", id).Count(&originalLanguageCount)
 	database.DB.Model(&models.Screening{}).Where("language_id = ? OR subtitle_language_id = ?", id, id).Count(&screeningCount)
 
-	if movieLanguageCount > 0 || screeningCount > 0 {
+	if movieLanguageCount > 0 || originalLanguageCount > 0 || screeningCount > 0 {
 		c.JSON(http.StatusConflict, utils.ErrorResponse("Cannot delete language that is used by movies or screenings"))
 		return
 	}
 
+	// Or by genre names and rating descriptions
+	var genreLanguageCount, ratingDescriptionCount int64
+	database.DB.Model(&models.GenreLanguage{}).Where("language_id = ?", id).Count(&genreLanguageCount)
+	database.DB.Model(&models.RatingDescription{}).Where("language_id = ?", id).Count(&ratingDescriptionCount)
+
+	if genreLanguageCount > 0 || ratingDescriptionCount > 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Cannot delete language that genres or ratings are translated into"))
+		return
+	}
+
 	if err := database.DB.Delete(&language).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete language"))
 		return
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/media"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/storage"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+var mediaExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// UploadMediaAsset - Upload a poster, backdrop or still for a movie. The file type is
+// sniffed from its content and resized JPEG/PNG and WebP variants are generated.
+func UploadMediaAsset(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	kind := models.MediaKind(c.PostForm("kind"))
+	widths, ok := models.MediaWidths[kind]
+	if !ok {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("kind must be poster, backdrop or still"))
+		return
+	}
+
+	maxBytes := int64(config.App.MediaMaxImageMB) << 20
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("file is required"))
+		return
+	}
+	if fileHeader.Size > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(fmt.Sprintf("Image is too large, the limit is %d MB", config.App.MediaMaxImageMB)))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read file"))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes))
+	file.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read file"))
+		return
+	}
+
+	contentType, err := media.SniffImage(data)
+	if err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	var existing models.MediaAsset
+	if err := database.DB.Where("movie_id = ? AND kind = ? AND checksum = ?", movie.ID, kind, checksum).
+		First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("This image has already been uploaded for the movie"))
+		return
+	}
+
+	imageConfig, renditions, err := media.ProcessImage(data, widths)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	// Keys include the checksum so a URL always refers to the same bytes and can be
+	// cached as immutable
+	prefix := fmt.Sprintf("media/%d/%s/%s", movie.ID, kind, checksum[:16])
+	asset := models.MediaAsset{
+		MovieID:     movie.ID,
+		Kind:        kind,
+		Key:         fmt.Sprintf("%s/original.%s", prefix, mediaExtensions[contentType]),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       imageConfig.Width,
+		Height:      imageConfig.Height,
+		Checksum:    checksum,
+		IsPrimary:   c.PostForm("is_primary") == "true",
+	}
+	asset.SortOrder, _ = strconv.Atoi(c.PostForm("sort_order"))
+
+	ctx := c.Request.Context()
+	stored := []string{asset.Key}
+	if err := storage.Default.Put(ctx, asset.Key, data, contentType); err != nil {
+		log.Printf("❌ Failed to store media %s: %v", asset.Key, err)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to store image"))
+		return
+	}
+	for _, rendition := range renditions {
+		variant := models.MediaVariant{
+			Name:        rendition.Name,
+			Format:      rendition.Format,
+			Width:       rendition.Width,
+			Height:      rendition.Height,
+			Key:         fmt.Sprintf("%s/%s.%s", prefix, rendition.Name, rendition.Format),
+			Size:        int64(len(rendition.Data)),
+			ContentType: rendition.ContentType,
+		}
+		if err := storage.Default.Put(ctx, variant.Key, rendition.Data, rendition.ContentType); err != nil {
+			log.Printf("❌ Failed to store media %s: %v", variant.Key, err)
+			deleteMediaObjects(c, stored)
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to store image"))
+			return
+		}
+		stored = append(stored, variant.Key)
+		asset.Variants = append(asset.Variants, variant)
+	}
+
+	if err := saveMediaAsset(&asset); err != nil {
+		deleteMediaObjects(c, stored)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save media asset"))
+		return
+	}
+
+	signMediaAsset(&asset)
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Image uploaded successfully", asset))
+}
+
+// AddTrailer - Add a trailer hosted on YouTube, Vimeo or another https URL
+func AddTrailer(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.TrailerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	videoURL, err := url.Parse(req.VideoURL)
+	if err != nil || videoURL.Scheme != "https" || videoURL.Host == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("video_url must be an https URL"))
+		return
+	}
+
+	if req.LanguageID != nil {
+		var language models.Language
+		if err := database.DB.First(&language, *req.LanguageID).Error; err != nil {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Language not found"))
+			return
+		}
+	}
+
+	asset := models.MediaAsset{
+		MovieID:         movie.ID,
+		Kind:            models.MediaTrailer,
+		Title:           req.Title,
+		VideoURL:        videoURL.String(),
+		Provider:        trailerProvider(videoURL.Hostname()),
+		DurationSeconds: req.DurationSeconds,
+		LanguageID:      req.LanguageID,
+		IsPrimary:       req.IsPrimary,
+	}
+
+	if err := saveMediaAsset(&asset); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save trailer"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Trailer added successfully", asset))
+}
+
+// GetMediaAssets - Get a movie's images and trailers with signed URLs, optionally ?kind=poster
+func GetMediaAssets(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Preload("Variants").Where("movie_id = ?", movie.ID)
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var assets []models.MediaAsset
+	if err := query.Order("kind ASC, is_primary DESC, sort_order ASC, id ASC").Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch media assets"))
+		return
+	}
+
+	for i := range assets {
+		signMediaAsset(&assets[i])
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Media assets retrieved successfully", assets))
+}
+
+// UpdateMediaAsset - Change whether an asset is primary, its sort order or a trailer's title
+func UpdateMediaAsset(c *gin.Context) {
+	asset, ok := loadMediaAsset(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateMediaAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	if req.IsPrimary != nil {
+		asset.IsPrimary = *req.IsPrimary
+	}
+	if req.SortOrder != nil {
+		asset.SortOrder = *req.SortOrder
+	}
+	if req.Title != nil {
+		asset.Title = *req.Title
+	}
+
+	if err := saveMediaAsset(&asset); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update media asset"))
+		return
+	}
+
+	signMediaAsset(&asset)
+	c.JSON(http.StatusOK, utils.SuccessResponse("Media asset updated successfully", asset))
+}
+
+// DeleteMediaAsset - Delete an image with its variants, or a trailer
+func DeleteMediaAsset(c *gin.Context) {
+	asset, ok := loadMediaAsset(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("asset_id = ?", asset.ID).Delete(&models.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&asset).Error; err != nil {
+			return err
+		}
+
+		// The next asset of the kind takes over as primary so the movie keeps its poster
+		if !asset.IsPrimary {
+			return nil
+		}
+		var next []models.MediaAsset
+		if err := tx.Where("movie_id = ? AND kind = ?", asset.MovieID, asset.Kind).
+			Order("sort_order ASC, id ASC").Limit(1).Find(&next).Error; err != nil {
+			return err
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		return tx.Model(&next[0]).Update("is_primary", true).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete media asset"))
+		return
+	}
+
+	var keys []string
+	if asset.Key != "" {
+		keys = append(keys, asset.Key)
+	}
+	for _, variant := range asset.Variants {
+		keys = append(keys, variant.Key)
+	}
+	deleteMediaObjects(c, keys)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Media asset deleted successfully", nil))
+}
+
+// ServeMedia - Serve a stored image through a signed URL. Objects never change under a
+// key, so responses are cacheable until the URL expires.
+func ServeMedia(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expiresAt, ok := media.Verify(key, c.Query("expires"), c.Query("sig"))
+	if !ok || !strings.HasPrefix(key, "media/") {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Invalid or expired media URL"))
+		return
+	}
+
+	etag := `"` + path.Base(path.Dir(key)) + "-" + strings.TrimSuffix(path.Base(key), path.Ext(key)) + `"`
+	maxAge := int(time.Until(expiresAt).Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", maxAge))
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	data, err := storage.Default.Get(c.Request.Context(), key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Media not found"))
+			return
+		}
+		log.Printf("❌ Failed to read media %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to read media"))
+		return
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+// attachPrimaryImages sets each movie's Poster and Backdrop to its primary uploaded
+// images, and points PosterURL at the poster so older clients pick it up
+func attachPrimaryImages(movies []models.Movie) {
+	if len(movies) == 0 {
+		return
+	}
+
+	movieIDs := make([]uint, len(movies))
+	for i, movie := range movies {
+		movieIDs[i] = movie.ID
+	}
+
+	var assets []models.MediaAsset
+	if err := database.DB.Preload("Variants").
+		Where("movie_id IN ? AND kind IN ? AND is_primary = ?", movieIDs, []models.MediaKind{models.MediaPoster, models.MediaBackdrop}, true).
+		Find(&assets).Error; err != nil {
+		// Movies are still served, with the stored poster URL
+		log.Printf("❌ Failed to load primary images: %v", err)
+		return
+	}
+
+	byMovie := make(map[uint]map[models.MediaKind]*models.MediaAsset)
+	for i := range assets {
+		asset := &assets[i]
+		signMediaAsset(asset)
+		if byMovie[asset.MovieID] == nil {
+			byMovie[asset.MovieID] = make(map[models.MediaKind]*models.MediaAsset)
+		}
+		byMovie[asset.MovieID][asset.Kind] = asset
+	}
+
+	for i := range movies {
+		movie := &movies[i]
+		movie.Poster = byMovie[movie.ID][models.MediaPoster]
+		movie.Backdrop = byMovie[movie.ID][models.MediaBackdrop]
+		if movie.Poster != nil {
+			movie.PosterURL = movie.Poster.URL
+		}
+	}
+}
+
+// saveMediaAsset saves an asset, making it the movie's only primary asset of its kind
+// when it is marked primary or is the first of its kind
+func saveMediaAsset(asset *models.MediaAsset) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var others int64
+		if err := tx.Model(&models.MediaAsset{}).
+			Where("movie_id = ? AND kind = ? AND id <> ?", asset.MovieID, asset.Kind, asset.ID).
+			Count(&others).Error; err != nil {
+			return err
+		}
+		if others == 0 {
+			asset.IsPrimary = true
+		}
+
+		if asset.IsPrimary {
+			if err := tx.Model(&models.MediaAsset{}).
+				Where("movie_id = ? AND kind = ? AND id <> ?", asset.MovieID, asset.Kind, asset.ID).
+				Update("is_primary", false).Error; err != nil {
+				return err
+			}
+		}
+
+		if asset.ID == 0 {
+			return tx.Create(asset).Error
+		}
+		return tx.Omit("Variants").Save(asset).Error
+	})
+}
+
+func signMediaAsset(asset *models.MediaAsset) {
+	if asset.Key != "" {
+		asset.URL = media.SignedURL(asset.Key)
+	}
+	for i := range asset.Variants {
+		asset.Variants[i].URL = media.SignedURL(asset.Variants[i].Key)
+	}
+}
+
+// deleteMediaObjects removes stored files, logging failures as the records are already gone
+func deleteMediaObjects(c *gin.Context, keys []string) {
+	for _, key := range keys {
+		if err := storage.Default.Delete(c.Request.Context(), key); err != nil && err != storage.ErrNotFound {
+			log.Printf("❌ Failed to delete media %s: %v", key, err)
+		}
+	}
+}
+
+func trailerProvider(host string) string {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	switch {
+	case host == "youtube.com" || host == "youtu.be" || strings.HasSuffix(host, ".youtube.com"):
+		return "youtube"
+	case host == "vimeo.com" || strings.HasSuffix(host, ".vimeo.com"):
+		return "vimeo"
+	}
+	return "other"
+}
+
+// loadMovie finds the movie addressed by :id, responding if it can't
+func loadMovie(c *gin.Context) (models.Movie, bool) {
+	var movie models.Movie
+
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
+		return movie, false
+	}
+
+	if err := database.DB.First(&movie, movieID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Movie not found"))
+			return movie, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return movie, false
+	}
+
+	return movie, true
+}
+
+// loadMediaAsset finds the asset addressed by :id and :assetId, responding if it can't
+func loadMediaAsset(c *gin.Context) (models.MediaAsset, bool) {
+	var asset models.MediaAsset
+
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
+		return asset, false
+	}
+
+	assetID, err := strconv.Atoi(c.Param("assetId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid asset ID"))
+		return asset, false
+	}
+
+	if err := database.DB.Preload("Variants").
+		Where("id = ? AND movie_id = ?", assetID, movieID).
+		First(&asset).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Media asset not found"))
+			return asset, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return asset, false
+	}
+
+	return asset, true
+}
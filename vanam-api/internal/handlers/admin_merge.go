@@ -0,0 +1,761 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	duplicateDefaultLimit    = 50
+	duplicateMaxLimit        = 200
+	duplicateDefaultMinScore = 0.5
+	duplicateMinSimilarity   = 0.3 // Pairs less alike than this aren't considered at all
+)
+
+// Pairs of live movies with similar original titles, with what else they have in common.
+// The % operator lets the trigram index on lower(original_title) find the pairs.
+const duplicateMoviesSQL = `
+SELECT a.id AS a_id, a.original_title AS a_name, b.id AS b_id, b.original_title AS b_name,
+	EXTRACT(YEAR FROM a.release_date)::int AS a_year, EXTRACT(YEAR FROM b.release_date)::int AS b_year,
+	similarity(lower(a.original_title), lower(b.original_title)) AS name_similarity,
+	(SELECT COUNT(DISTINCT ca.person_id) FROM movie_cast ca JOIN movie_cast cb ON cb.person_id = ca.person_id
+		WHERE ca.movie_id = a.id AND cb.movie_id = b.id) AS shared_cast,
+	(SELECT COUNT(*) FROM movie_cast WHERE movie_id = a.id) AS a_credits,
+	(SELECT COUNT(*) FROM movie_cast WHERE movie_id = b.id) AS b_credits,
+	(SELECT COUNT(*) FROM screenings WHERE movie_id = a.id) AS a_screenings,
+	(SELECT COUNT(*) FROM screenings WHERE movie_id = b.id) AS b_screenings
+FROM movies a
+JOIN movies b ON a.id < b.id AND lower(a.original_title) % lower(b.original_title)
+WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+	AND similarity(lower(a.original_title), lower(b.original_title)) >= @min_similarity
+ORDER BY name_similarity DESC, a.id
+LIMIT @candidates`
+
+// Pairs of people with similar names. Duplicates made by separate imports rarely share a
+// movie, but they tend to have worked with the same people.
+const duplicatePeopleSQL = `
+SELECT a.id AS a_id, a.name AS a_name, b.id AS b_id, b.name AS b_name,
+	similarity(lower(a.name), lower(b.name)) AS name_similarity,
+	(SELECT COUNT(DISTINCT ca.movie_id) FROM movie_cast ca JOIN movie_cast cb ON cb.movie_id = ca.movie_id
+		WHERE ca.person_id = a.id AND cb.person_id = b.id) AS shared_movies,
+	(SELECT COUNT(DISTINCT xa.person_id) FROM movie_cast ca
+		JOIN movie_cast xa ON xa.movie_id = ca.movie_id
+		JOIN movie_cast xb ON xb.person_id = xa.person_id
+		JOIN movie_cast cb ON cb.movie_id = xb.movie_id
+		WHERE ca.person_id = a.id AND cb.person_id = b.id AND xa.person_id NOT IN (a.id, b.id)) AS shared_cast,
+	(SELECT COUNT(*) FROM movie_cast WHERE person_id = a.id) AS a_credits,
+	(SELECT COUNT(*) FROM movie_cast WHERE person_id = b.id) AS b_credits
+FROM people a
+JOIN people b ON a.id < b.id AND lower(a.name) % lower(b.name)
+WHERE similarity(lower(a.name), lower(b.name)) >= @min_similarity
+ORDER BY name_similarity DESC, a.id
+LIMIT @candidates`
+
+type duplicatePair struct {
+	AID            uint    `gorm:"column:a_id"`
+	AName          string  `gorm:"column:a_name"`
+	AYear          int     `gorm:"column:a_year"`
+	ACredits       int     `gorm:"column:a_credits"`
+	AScreenings    int     `gorm:"column:a_screenings"`
+	BID            uint    `gorm:"column:b_id"`
+	BName          string  `gorm:"column:b_name"`
+	BYear          int     `gorm:"column:b_year"`
+	BCredits       int     `gorm:"column:b_credits"`
+	BScreenings    int     `gorm:"column:b_screenings"`
+	NameSimilarity float64 `gorm:"column:name_similarity"`
+	SharedCast     int     `gorm:"column:shared_cast"`
+	SharedMovies   int     `gorm:"column:shared_movies"`
+}
+
+// mergeError carries the HTTP status a merge or undo failure should be reported with
+type mergeError struct {
+	Status  int
+	Message string
+}
+
+func (e *mergeError) Error() string {
+	return e.Message
+}
+
+// FindDuplicateMovies - List pairs of movies that are probably the same, by title, release year and shared cast (admin only)
+func FindDuplicateMovies(c *gin.Context) {
+	findDuplicates(c, models.MergeMovie)
+}
+
+// FindDuplicatePeople - List pairs of people who are probably the same, by name and shared collaborators (admin only)
+func FindDuplicatePeople(c *gin.Context) {
+	findDuplicates(c, models.MergePerson)
+}
+
+func findDuplicates(c *gin.Context, kind models.MergeKind) {
+	if !database.TrigramSearch {
+		c.JSON(http.StatusServiceUnavailable, utils.ErrorResponse("Duplicate detection needs the pg_trgm database extension"))
+		return
+	}
+
+	minScore := duplicateDefaultMinScore
+	if s := c.Query("min_score"); s != "" {
+		parsed, err := strconv.ParseFloat(s, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("min_score must be between 0 and 1"))
+			return
+		}
+		minScore = parsed
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(duplicateDefaultLimit)))
+	if err != nil || limit <= 0 {
+		limit = duplicateDefaultLimit
+	}
+	if limit > duplicateMaxLimit {
+		limit = duplicateMaxLimit
+	}
+
+	query := duplicateMoviesSQL
+	if kind == models.MergePerson {
+		query = duplicatePeopleSQL
+	}
+
+	// Similar names are fetched generously as the year and shared cast can sink a pair
+	var pairs []duplicatePair
+	if err := database.DB.Raw(query,
+		sql.Named("min_similarity", duplicateMinSimilarity),
+		sql.Named("candidates", limit*5),
+	).Scan(&pairs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to find duplicates"))
+		return
+	}
+
+	candidates := []dtos.DuplicateCandidate{}
+	for _, pair := range pairs {
+		candidate := duplicateCandidate(kind, pair)
+		if candidate.Score >= minScore {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Duplicates retrieved successfully", candidates))
+}
+
+// duplicateCandidate scores a pair. Name similarity counts most; movies gain from a
+// matching release year and shared cast, people from shared collaborators and from being
+// credited on the same movie.
+func duplicateCandidate(kind models.MergeKind, pair duplicatePair) dtos.DuplicateCandidate {
+	a := dtos.DuplicateRecord{ID: pair.AID, Name: pair.AName, Credits: pair.ACredits}
+	b := dtos.DuplicateRecord{ID: pair.BID, Name: pair.BName, Credits: pair.BCredits}
+	candidate := dtos.DuplicateCandidate{
+		Kind:           string(kind),
+		NameSimilarity: pair.NameSimilarity,
+		SharedCast:     pair.SharedCast,
+		SharedMovies:   pair.SharedMovies,
+	}
+
+	var score float64
+	if kind == models.MergeMovie {
+		a.Year, a.Screenings = pair.AYear, pair.AScreenings
+		b.Year, b.Screenings = pair.BYear, pair.BScreenings
+
+		yearGap := pair.AYear - pair.BYear
+		if yearGap < 0 {
+			yearGap = -yearGap
+		}
+		candidate.YearGap = &yearGap
+
+		score = 0.6 * pair.NameSimilarity
+		switch yearGap {
+		case 0:
+			score += 0.25
+		case 1:
+			score += 0.1
+		}
+		score += 0.15 * float64(min(pair.SharedCast, 3)) / 3
+	} else {
+		score = 0.7 * pair.NameSimilarity
+		score += 0.2 * float64(min(pair.SharedCast, 5)) / 5
+		if pair.SharedMovies > 0 {
+			score += 0.1
+		}
+	}
+	candidate.Score = float64(int(score*1000+0.5)) / 1000
+	candidate.Records = []dtos.DuplicateRecord{a, b}
+
+	// Keep the record more of the catalog points at, or the older one
+	candidate.SuggestedSurvivorID = a.ID
+	if b.Credits+b.Screenings > a.Credits+a.Screenings {
+		candidate.SuggestedSurvivorID = b.ID
+	}
+	return candidate
+}
+
+// MergeDuplicate - Merge a duplicate movie or person into the record that survives, recording the merge so it can be undone (admin only)
+func MergeDuplicate(c *gin.Context) {
+	var req dtos.MergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	record := models.MergeRecord{
+		Kind:        models.MergeKind(req.Kind),
+		SurvivorID:  req.SurvivorID,
+		DuplicateID: req.DuplicateID,
+		MergedBy:    c.GetUint("user_id"),
+	}
+	var changes models.MergeChanges
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if record.Kind == models.MergeMovie {
+			changes, err = mergeMovies(tx, record.SurvivorID, record.DuplicateID)
+		} else {
+			changes, err = mergePeople(tx, record.SurvivorID, record.DuplicateID)
+		}
+		if err != nil {
+			return err
+		}
+
+		changesJSON, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		record.Changes = string(changesJSON)
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		if mergeErr, ok := err.(*mergeError); ok {
+			c.JSON(mergeErr.Status, utils.ErrorResponse(mergeErr.Message))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to merge records"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Records merged successfully", mergeSummary(record, changes)))
+}
+
+// mergeMovies moves the duplicate's credits, genres, localizations, screenings, media,
+// certifications and advisories to the survivor and deletes the duplicate. Credits and
+// genres the survivor already has are dropped; localizations and certifications the
+// survivor already has stay on the duplicate. The survivor takes the duplicate's
+// external ID if it has none, so re-importing the duplicate's bundle updates it.
+func mergeMovies(tx *gorm.DB, survivorID, duplicateID uint) (models.MergeChanges, error) {
+	var changes models.MergeChanges
+
+	var survivor, duplicate models.Movie
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&survivor, survivorID).Error; err != nil {
+		return changes, notFoundOr(err, "Surviving movie not found")
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&duplicate, duplicateID).Error; err != nil {
+		return changes, notFoundOr(err, "Duplicate movie not found")
+	}
+
+	// Credits
+	var survivorCredits, duplicateCredits []models.MovieCast
+	if err := tx.Where("movie_id = ?", survivorID).Find(&survivorCredits).Error; err != nil {
+		return changes, err
+	}
+	if err := tx.Where("movie_id = ?", duplicateID).Find(&duplicateCredits).Error; err != nil {
+		return changes, err
+	}
+	credited := make(map[string]bool)
+	for _, credit := range survivorCredits {
+		credited[creditKey(credit.PersonID, credit.Role)] = true
+	}
+	for _, credit := range duplicateCredits {
+		if !credited[creditKey(credit.PersonID, credit.Role)] {
+			changes.MovedCredits = append(changes.MovedCredits, models.NewMergedCredit(credit))
+			continue
+		}
+		changes.DroppedCredits = append(changes.DroppedCredits, models.NewMergedCredit(credit))
+		if err := tx.Where("movie_id = ? AND person_id = ? AND role = ?", duplicateID, credit.PersonID, credit.Role).
+			Delete(&models.MovieCast{}).Error; err != nil {
+			return changes, err
+		}
+	}
+	if err := tx.Model(&models.MovieCast{}).Where("movie_id = ?", duplicateID).Update("movie_id", survivorID).Error; err != nil {
+		return changes, err
+	}
+
+	// Genres
+	var survivorGenreIDs, duplicateGenreIDs []uint
+	if err := tx.Table("movie_genres").Where("movie_id = ?", survivorID).Pluck("genre_id", &survivorGenreIDs).Error; err != nil {
+		return changes, err
+	}
+	if err := tx.Table("movie_genres").Where("movie_id = ?", duplicateID).Pluck("genre_id", &duplicateGenreIDs).Error; err != nil {
+		return changes, err
+	}
+	hasGenre := make(map[uint]bool)
+	for _, genreID := range survivorGenreIDs {
+		hasGenre[genreID] = true
+	}
+	for _, genreID := range duplicateGenreIDs {
+		if hasGenre[genreID] {
+			changes.DroppedGenreIDs = append(changes.DroppedGenreIDs, genreID)
+		} else {
+			changes.MovedGenreIDs = append(changes.MovedGenreIDs, genreID)
+		}
+	}
+	if len(changes.DroppedGenreIDs) > 0 {
+		if err := tx.Exec("DELETE FROM movie_genres WHERE movie_id = ? AND genre_id IN ?", duplicateID, changes.DroppedGenreIDs).Error; err != nil {
+			return changes, err
+		}
+	}
+	if err := tx.Exec("UPDATE movie_genres SET movie_id = ? WHERE movie_id = ?", survivorID, duplicateID).Error; err != nil {
+		return changes, err
+	}
+
+	// Localizations
+	var survivorLanguages, duplicateLanguages []models.MovieLanguage
+	if err := tx.Where("movie_id = ?", survivorID).Find(&survivorLanguages).Error; err != nil {
+		return changes, err
+	}
+	if err := tx.Where("movie_id = ?", duplicateID).Find(&duplicateLanguages).Error; err != nil {
+		return changes, err
+	}
+	hasLanguage := make(map[uint]bool)
+	for _, ml := range survivorLanguages {
+		hasLanguage[ml.LanguageID] = true
+	}
+	for _, ml := range duplicateLanguages {
+		if hasLanguage[ml.LanguageID] {
+			changes.KeptMovieLanguageIDs = append(changes.KeptMovieLanguageIDs, ml.ID)
+		} else {
+			changes.MovedMovieLanguageIDs = append(changes.MovedMovieLanguageIDs, ml.ID)
+		}
+	}
+	if len(changes.MovedMovieLanguageIDs) > 0 {
+		if err := tx.Model(&models.MovieLanguage{}).Where("id IN ?", changes.MovedMovieLanguageIDs).
+			Update("movie_id", survivorID).Error; err != nil {
+			return changes, err
+		}
+	}
+
+	// Screenings, and with them their bookings
+	if err := tx.Model(&models.Screening{}).Where("movie_id = ?", duplicateID).Pluck("id", &changes.MovedScreeningIDs).Error; err != nil {
+		return changes, err
+	}
+	if len(changes.MovedScreeningIDs) > 0 {
+		if err := tx.Model(&models.Screening{}).Where("id IN ?", changes.MovedScreeningIDs).
+			Update("movie_id", survivorID).Error; err != nil {
+			return changes, err
+		}
+	}
+
+	// Media. The survivor's primary images stay primary.
+	var survivorAssets, duplicateAssets []models.MediaAsset
+	if err := tx.Where("movie_id = ?", survivorID).Find(&survivorAssets).Error; err != nil {
+		return changes, err
+	}
+	if err := tx.Where("movie_id = ?", duplicateID).Find(&duplicateAssets).Error; err != nil {
+		return changes, err
+	}
+	hasPrimary := make(map[models.MediaKind]bool)
+	for _, asset := range survivorAssets {
+		if asset.IsPrimary {
+			hasPrimary[asset.Kind] = true
+		}
+	}
+	for _, asset := range duplicateAssets {
+		changes.MovedAssetIDs = append(changes.MovedAssetIDs, asset.ID)
+		if asset.IsPrimary && hasPrimary[asset.Kind] {
+			changes.DemotedAssetIDs = append(changes.DemotedAssetIDs, asset.ID)
+		}
+	}
+	if len(changes.DemotedAssetIDs) > 0 {
+		if err := tx.Model(&models.MediaAsset{}).Where("id IN ?", changes.DemotedAssetIDs).Update("is_primary", false).Error; err != nil {
+			return changes, err
+		}
+	}
+	if len(changes.MovedAssetIDs) > 0 {
+		if err := tx.Model(&models.MediaAsset{}).Where("id IN ?", changes.MovedAssetIDs).Update("movie_id", survivorID).Error; err != nil {
+			return changes, err
+		}
+	}
+
+	// Certifications, one per system
+	var survivorCertifications, duplicateCertifications []models.MovieCertification
+	if err := tx.Where("movie_id = ?", survivorID).Find(&survivorCertifications).Error; err != nil {
+		return changes, err
+	}
+	if err := tx.Where("movie_id = ?", duplicateID).Find(&duplicateCertifications).Error; err != nil {
+		return changes, err
+	}
+	certified := make(map[uint]bool)
+	for _, mc := range survivorCertifications {
+		certified[mc.SystemID] = true
+	}
+	for _, mc := range duplicateCertifications {
+		if certified[mc.SystemID] {
+			changes.KeptCertificationIDs = append(changes.KeptCertificationIDs, mc.ID)
+		} else {
+			changes.MovedCertificationIDs = append(changes.MovedCertificationIDs, mc.ID)
+		}
+	}
+	if len(changes.MovedCertificationIDs) > 0 {
+		if err := tx.Model(&models.MovieCertification{}).Where("id IN ?", changes.MovedCertificationIDs).
+			Update("movie_id", survivorID).Error; err != nil {
+			return changes, err
+		}
+	}
+
+	// Advisories
+	if err := tx.Model(&models.ContentAdvisory{}).Where("movie_id = ?", duplicateID).Pluck("id", &changes.MovedAdvisoryIDs).Error; err != nil {
+		return changes, err
+	}
+	if len(changes.MovedAdvisoryIDs) > 0 {
+		if err := tx.Model(&models.ContentAdvisory{}).Where("id IN ?", changes.MovedAdvisoryIDs).
+			Update("movie_id", survivorID).Error; err != nil {
+			return changes, err
+		}
+	}
+
+	// External ID, cleared on the duplicate first as it is unique
+	if survivor.ExternalID == nil && duplicate.ExternalID != nil {
+		changes.MovedExternalID = duplicate.ExternalID
+		if err := tx.Unscoped().Model(&models.Movie{}).Where("id = ?", duplicateID).Update("external_id", nil).Error; err != nil {
+			return changes, err
+		}
+		if err := tx.Model(&models.Movie{}).Where("id = ?", survivorID).Update("external_id", *duplicate.ExternalID).Error; err != nil {
+			return changes, err
+		}
+	}
+
+	if err := tx.Delete(&duplicate).Error; err != nil {
+		return changes, err
+	}
+	return changes, refreshMovieStatus(tx, survivorID)
+}
+
+// mergePeople moves the duplicate's credits to the survivor and deletes the duplicate.
+// Credits the survivor already has on the same movie in the same role are dropped.
+func mergePeople(tx *gorm.DB, survivorID, duplicateID uint) (models.MergeChanges, error) {
+	var changes models.MergeChanges
+
+	var survivor, duplicate models.Person
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&survivor, survivorID).Error; err != nil {
+		return changes, notFoundOr(err, "Surviving person not found")
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&duplicate, duplicateID).Error; err != nil {
+		return changes, notFoundOr(err, "Duplicate person not found")
+	}
+
+	var survivorCredits, duplicateCredits []models.MovieCast
+	if err := tx.Where("person_id = ?", survivorID).Find(&survivorCredits).Error; err != nil {
+		return changes, err
+	}
+	if err := tx.Where("person_id = ?", duplicateID).Find(&duplicateCredits).Error; err != nil {
+		return changes, err
+	}
+	credited := make(map[string]bool)
+	for _, credit := range survivorCredits {
+		credited[creditKey(credit.MovieID, credit.Role)] = true
+	}
+	for _, credit := range duplicateCredits {
+		if !credited[creditKey(credit.MovieID, credit.Role)] {
+			changes.MovedCredits = append(changes.MovedCredits, models.NewMergedCredit(credit))
+			continue
+		}
+		changes.DroppedCredits = append(changes.DroppedCredits, models.NewMergedCredit(credit))
+		if err := tx.Where("movie_id = ? AND person_id = ? AND role = ?", credit.MovieID, duplicateID, credit.Role).
+			Delete(&models.MovieCast{}).Error; err != nil {
+			return changes, err
+		}
+	}
+	if err := tx.Model(&models.MovieCast{}).Where("person_id = ?", duplicateID).Update("person_id", survivorID).Error; err != nil {
+		return changes, err
+	}
+
+	changes.Person = &duplicate
+	return changes, tx.Delete(&duplicate).Error
+}
+
+// GetMerges - List merges, newest first, with ?kind=movie|person and ?record_id= to find merges involving a record (admin only)
+func GetMerges(c *gin.Context) {
+	var records []models.MergeRecord
+	query := database.DB.Model(&models.MergeRecord{})
+
+	// Pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if recordID := c.Query("record_id"); recordID != "" {
+		query = query.Where("survivor_id = ? OR duplicate_id = ?", recordID, recordID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch merges"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginationResponse("Merges retrieved successfully", records, page, limit, total))
+}
+
+// UndoMerge - Undo a merge, bringing back the duplicate with what was moved from it (admin only)
+func UndoMerge(c *gin.Context) {
+	mergeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid merge ID"))
+		return
+	}
+
+	var record models.MergeRecord
+	var changes models.MergeChanges
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&record, mergeID).Error; err != nil {
+			return notFoundOr(err, "Merge not found")
+		}
+		if record.UndoneAt != nil {
+			return &mergeError{http.StatusConflict, "Merge has already been undone"}
+		}
+
+		// Later merges may have moved the same rows again, so they are undone first
+		ids := []uint{record.SurvivorID, record.DuplicateID}
+		var later int64
+		if err := tx.Model(&models.MergeRecord{}).
+			Where("kind = ? AND id > ? AND undone_at IS NULL AND (survivor_id IN ? OR duplicate_id IN ?)", record.Kind, record.ID, ids, ids).
+			Count(&later).Error; err != nil {
+			return err
+		}
+		if later > 0 {
+			return &mergeError{http.StatusConflict, "A later merge involves these records, undo it first"}
+		}
+
+		if err := json.Unmarshal([]byte(record.Changes), &changes); err != nil {
+			return err
+		}
+		var undoErr error
+		if record.Kind == models.MergeMovie {
+			undoErr = undoMovieMerge(tx, record, changes)
+		} else {
+			undoErr = undoPersonMerge(tx, record, changes)
+		}
+		if undoErr != nil {
+			return undoErr
+		}
+
+		now := time.Now()
+		undoneBy := c.GetUint("user_id")
+		record.UndoneAt = &now
+		record.UndoneBy = &undoneBy
+		return tx.Model(&record).Updates(map[string]interface{}{
+			"undone_at": now,
+			"undone_by": undoneBy,
+		}).Error
+	})
+	if err != nil {
+		if mergeErr, ok := err.(*mergeError); ok {
+			c.JSON(mergeErr.Status, utils.ErrorResponse(mergeErr.Message))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to undo merge"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Merge undone successfully", mergeSummary(record, changes)))
+}
+
+// undoMovieMerge restores the duplicate movie and moves back whatever of its credits,
+// genres, localizations, screenings, media, certifications, advisories and external ID
+// is still on the survivor
+func undoMovieMerge(tx *gorm.DB, record models.MergeRecord, changes models.MergeChanges) error {
+	restored := tx.Unscoped().Model(&models.Movie{}).Where("id = ?", record.DuplicateID).Update("deleted_at", nil)
+	if restored.Error != nil {
+		return restored.Error
+	}
+	if restored.RowsAffected == 0 {
+		return &mergeError{http.StatusConflict, "The merged movie no longer exists"}
+	}
+
+	for _, credit := range changes.MovedCredits {
+		if err := tx.Model(&models.MovieCast{}).
+			Where("movie_id = ? AND person_id = ? AND role = ?", record.SurvivorID, credit.PersonID, credit.Role).
+			Updates(map[string]interface{}{
+				"movie_id":       record.DuplicateID,
+				"character_name": credit.CharacterName,
+				"billing_order":  credit.BillingOrder,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	if err := restoreCredits(tx, changes.DroppedCredits); err != nil {
+		return err
+	}
+
+	if len(changes.MovedGenreIDs) > 0 {
+		if err := tx.Exec("UPDATE movie_genres SET movie_id = ? WHERE movie_id = ? AND genre_id IN ?",
+			record.DuplicateID, record.SurvivorID, changes.MovedGenreIDs).Error; err != nil {
+			return err
+		}
+	}
+	for _, genreID := range changes.DroppedGenreIDs {
+		if err := tx.Exec("INSERT INTO movie_genres (movie_id, genre_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			record.DuplicateID, genreID).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(changes.MovedMovieLanguageIDs) > 0 {
+		if err := tx.Model(&models.MovieLanguage{}).
+			Where("id IN ? AND movie_id = ?", changes.MovedMovieLanguageIDs, record.SurvivorID).
+			Update("movie_id", record.DuplicateID).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(changes.MovedScreeningIDs) > 0 {
+		if err := tx.Model(&models.Screening{}).
+			Where("id IN ? AND movie_id = ?", changes.MovedScreeningIDs, record.SurvivorID).
+			Update("movie_id", record.DuplicateID).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(changes.MovedAssetIDs) > 0 {
+		if err := tx.Model(&models.MediaAsset{}).
+			Where("id IN ? AND movie_id = ?", changes.MovedAssetIDs, record.SurvivorID).
+			Update("movie_id", record.DuplicateID).Error; err != nil {
+			return err
+		}
+	}
+	if len(changes.DemotedAssetIDs) > 0 {
+		if err := tx.Model(&models.MediaAsset{}).
+			Where("id IN ? AND movie_id = ?", changes.DemotedAssetIDs, record.DuplicateID).
+			Update("is_primary", true).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(changes.MovedCertificationIDs) > 0 {
+		if err := tx.Model(&models.MovieCertification{}).
+			Where("id IN ? AND movie_id = ?", changes.MovedCertificationIDs, record.SurvivorID).
+			Update("movie_id", record.DuplicateID).Error; err != nil {
+			return err
+		}
+	}
+	if len(changes.MovedAdvisoryIDs) > 0 {
+		if err := tx.Model(&models.ContentAdvisory{}).
+			Where("id IN ? AND movie_id = ?", changes.MovedAdvisoryIDs, record.SurvivorID).
+			Update("movie_id", record.DuplicateID).Error; err != nil {
+			return err
+		}
+	}
+
+	if changes.MovedExternalID != nil {
+		if err := tx.Unscoped().Model(&models.Movie{}).
+			Where("id = ? AND external_id = ?", record.SurvivorID, *changes.MovedExternalID).
+			Update("external_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Movie{}).Where("id = ?", record.DuplicateID).
+			Update("external_id", *changes.MovedExternalID).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := refreshMovieStatus(tx, record.SurvivorID); err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return refreshMovieStatus(tx, record.DuplicateID)
+}
+
+// undoPersonMerge recreates the duplicate person under their old ID and moves back
+// whatever of their credits is still on the survivor
+func undoPersonMerge(tx *gorm.DB, record models.MergeRecord, changes models.MergeChanges) error {
+	if changes.Person == nil {
+		return &mergeError{http.StatusConflict, "Merge record has no copy of the merged person"}
+	}
+
+	var existing int64
+	if err := tx.Model(&models.Person{}).Where("id = ?", record.DuplicateID).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return &mergeError{http.StatusConflict, "The merged person already exists again"}
+	}
+	person := *changes.Person
+	person.Movies = nil
+	if err := tx.Omit(clause.Associations).Create(&person).Error; err != nil {
+		return err
+	}
+
+	for _, credit := range changes.MovedCredits {
+		if err := tx.Model(&models.MovieCast{}).
+			Where("movie_id = ? AND person_id = ? AND role = ?", credit.MovieID, record.SurvivorID, credit.Role).
+			Updates(map[string]interface{}{
+				"person_id":      record.DuplicateID,
+				"character_name": credit.CharacterName,
+				"billing_order":  credit.BillingOrder,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return restoreCredits(tx, changes.DroppedCredits)
+}
+
+// restoreCredits recreates credits a merge dropped, skipping any that exist again
+func restoreCredits(tx *gorm.DB, credits []models.MergedCredit) error {
+	if len(credits) == 0 {
+		return nil
+	}
+	rows := make([]models.MovieCast, len(credits))
+	for i, credit := range credits {
+		rows[i] = credit.MovieCast()
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(&rows).Error
+}
+
+func mergeSummary(record models.MergeRecord, changes models.MergeChanges) dtos.MergeSummary {
+	return dtos.MergeSummary{
+		MergeID:        record.ID,
+		Kind:           string(record.Kind),
+		SurvivorID:     record.SurvivorID,
+		DuplicateID:    record.DuplicateID,
+		Credits:        len(changes.MovedCredits),
+		DroppedCredits: len(changes.DroppedCredits),
+		Genres:         len(changes.MovedGenreIDs),
+		Languages:      len(changes.MovedMovieLanguageIDs),
+		KeptLanguages:  len(changes.KeptMovieLanguageIDs),
+		Screenings:     len(changes.MovedScreeningIDs),
+		Assets:         len(changes.MovedAssetIDs),
+		Certifications: len(changes.MovedCertificationIDs),
+		Advisories:     len(changes.MovedAdvisoryIDs),
+		ExternalID:     changes.MovedExternalID != nil,
+	}
+}
+
+func creditKey(id uint, role models.CreditRole) string {
+	return strconv.FormatUint(uint64(id), 10) + ":" + string(role)
+}
+
+// notFoundOr turns a missing record into a 404 with the message given
+func notFoundOr(err error, msg string) error {
+	if err == gorm.ErrRecordNotFound {
+		return &mergeError{http.StatusNotFound, msg}
+	}
+	return err
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
@@ -20,6 +22,23 @@ func CreateMovie(c *gin.Context) {
 		return
 	}
 
+	comingSoonAt, bookingOpensAt := defaultLifecycleDates(req.ReleaseDate)
+	if req.ComingSoonAt != nil {
+		comingSoonAt = *req.ComingSoonAt
+	}
+	if req.BookingOpensAt != nil {
+		bookingOpensAt = *req.BookingOpensAt
+	}
+	if msg := validateLifecycleDates(comingSoonAt, bookingOpensAt, req.ReleaseDate); msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+
+	// Ratings are the default country's certification codes
+	if !checkRating(c, string(req.Rating)) {
+		return
+	}
+
 	// Validate genres exist
 	var genreCount int64
 	if err := database.DB.Model(&models.Genre{}).Where("id IN ?", req.GenreIDs).Count(&genreCount).Error; err != nil {
@@ -44,6 +63,15 @@ func CreateMovie(c *gin.Context) {
 		}
 	}
 
+	// Validate original language if provided
+	if req.OriginalLanguageID != nil {
+		var language models.Language
+		if err := database.DB.First(&language, *req.OriginalLanguageID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Original language not found"))
+			return
+		}
+	}
+
 	// Start transaction
 	tx := database.DB.Begin()
 	defer func() {
@@ -61,7 +89,16 @@ func CreateMovie(c *gin.Context) {
 		Description:   req.Description,
 		PosterURL:     req.PosterURL,
 		IsActive:      true,
+
+		ComingSoonAt:   &comingSoonAt,
+		BookingOpensAt: &bookingOpensAt,
+
+		IMDbID:             req.IMDbID,
+		TMDBID:             req.TMDBID,
+		OriginalLanguageID: req.OriginalLanguageID,
+		CountryOfOrigin:    strings.ToUpper(req.CountryOfOrigin),
 	}
+	movie.Status = movie.StatusAt(time.Now(), nil)
 
 	if err := tx.Create(&movie).Error; err != nil {
 		tx.Rollback()
@@ -82,15 +119,24 @@ func CreateMovie(c *gin.Context) {
 		return
 	}
 
-	// Associate cast (if provided)
-	if len(req.CastIDs) > 0 {
-		var persons []models.Person
-		if err := tx.Where("id IN ?", req.CastIDs).Find(&persons).Error; err != nil {
+	// Associate cast and crew (if provided), billed in the order given
+	if len(req.Credits) > 0 {
+		credits, msg, err := resolveCredits(tx, movie.ID, req.Credits)
+		if msg != "" {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+			return
+		}
+		if err == nil {
+			err = replaceCredits(tx, movie.ID, credits)
+		}
+		if err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch cast"))
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to associate credits"))
 			return
 		}
-		if err := tx.Model(&movie).Association("Cast").Replace(&persons); err != nil {
+	} else if len(req.CastIDs) > 0 {
+		if err := replaceActors(tx, movie.ID, req.CastIDs); err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to associate cast"))
 			return
@@ -104,7 +150,8 @@ func CreateMovie(c *gin.Context) {
 	}
 
 	// Reload movie with associations
-	database.DB.Preload("Genres").Preload("Cast").First(&movie, movie.ID)
+	preloadCredits(database.DB.Preload("Genres").Preload("OriginalLanguage")).First(&movie, movie.ID)
+	movie.ArrangeCredits()
 
 	c.JSON(http.StatusCreated, utils.SuccessResponse("Movie created successfully", movie))
 }
@@ -112,7 +159,7 @@ func CreateMovie(c *gin.Context) {
 // GetAllMovies - Get all movies with pagination and proper filtering
 func GetAllMovies(c *gin.Context) {
 	var movies []models.Movie
-	query := database.DB.Preload("Genres").Preload("Cast")
+	query := preloadCredits(database.DB.Preload("Genres"))
 
 	// Pagination
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -121,7 +168,8 @@ func GetAllMovies(c *gin.Context) {
 
 	// Filters
 	if search := c.Query("search"); search != "" {
-		query = query.Where("original_title ILIKE ?", "%"+search+"%")
+		pattern := "%" + search + "%"
+		query = query.Where("original_title ILIKE ? OR EXISTS (SELECT 1 FROM movie_languages WHERE movie_languages.movie_id = movies.id AND movie_languages.title ILIKE ?)", pattern, pattern)
 	}
 
 	// Filter by genre
@@ -146,6 +194,11 @@ func GetAllMovies(c *gin.Context) {
 		query = query.Where("is_active = ?", isActive == "true")
 	}
 
+	// Filter by lifecycle status, e.g. status=now_showing,advance_booking
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status IN ?", strings.Split(status, ","))
+	}
+
 	// Get total count
 	var total int64
 	query.Model(&models.Movie{}).Count(&total)
@@ -156,13 +209,16 @@ func GetAllMovies(c *gin.Context) {
 		return
 	}
 
+	arrangeCredits(movies)
+	localizeMovies(c, movies)
+	attachPrimaryImages(movies)
+
 	c.JSON(http.StatusOK, utils.PaginationResponse("Movies retrieved successfully", movies, page, limit, total))
 }
 
 // Update existing GetMovieByID handler
 func GetMovieByID(c *gin.Context) {
 	movieID := c.Param("id")
-	preferredLang := c.Query("lang") // Optional language parameter
 
 	id, err := strconv.Atoi(movieID)
 	if err != nil {
@@ -171,27 +227,27 @@ func GetMovieByID(c *gin.Context) {
 	}
 
 	var movie models.Movie
-	query := database.DB.
+	query := preloadCredits(database.DB).
 		Preload("Genres").
-		Preload("Cast").
+		Preload("OriginalLanguage").
 		Preload("MovieLanguages.Language").
-		Preload("Screenings")
+		Preload("Screenings").
+		Preload("Certifications.Certification").
+		Preload("Certifications.System").
+		Preload("Advisories")
 
 	if err := query.First(&movie, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, utils.ErrorResponse("Movie not found"))
 		return
 	}
 
-	// If specific language requested, prioritize localized content
-	if preferredLang != "" {
-		for _, ml := range movie.MovieLanguages {
-			if ml.Language.Code == preferredLang {
-				movie.OriginalTitle = ml.Title
-				movie.Description = ml.Description
-				break
-			}
-		}
-	}
+	movie.ArrangeCredits()
+	attachTrailers(&movie)
+
+	movies := []models.Movie{movie}
+	localizeMovies(c, movies)
+	attachPrimaryImages(movies)
+	movie = movies[0]
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Movie retrieved successfully", movie))
 }
@@ -247,6 +303,51 @@ func UpdateMovie(c *gin.Context) {
 		}
 	}
 
+	if req.Rating != nil && !checkRating(c, string(*req.Rating)) {
+		return
+	}
+
+	// Empty external IDs and origin clear them, so they are checked here rather than by binding
+	if req.IMDbID != nil && *req.IMDbID != "" && !strings.HasPrefix(*req.IMDbID, "tt") {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("imdb_id must start with tt"))
+		return
+	}
+	if req.CountryOfOrigin != nil && *req.CountryOfOrigin != "" && !isCountryCode(*req.CountryOfOrigin) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("country_of_origin must be a two-letter country code"))
+		return
+	}
+
+	// Validate original language if provided
+	if req.OriginalLanguageID != nil && *req.OriginalLanguageID != 0 {
+		var language models.Language
+		if err := database.DB.First(&language, *req.OriginalLanguageID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Original language not found"))
+			return
+		}
+	}
+
+	// Lifecycle dates move with the release date unless they are given explicitly
+	releaseDate := movie.ReleaseDate
+	if req.ReleaseDate != nil {
+		releaseDate = *req.ReleaseDate
+	}
+	shift := releaseDate.Sub(movie.ReleaseDate)
+	comingSoonAt, bookingOpensAt := defaultLifecycleDates(releaseDate)
+	if req.ComingSoonAt != nil {
+		comingSoonAt = *req.ComingSoonAt
+	} else if movie.ComingSoonAt != nil {
+		comingSoonAt = movie.ComingSoonAt.Add(shift)
+	}
+	if req.BookingOpensAt != nil {
+		bookingOpensAt = *req.BookingOpensAt
+	} else if movie.BookingOpensAt != nil {
+		bookingOpensAt = movie.BookingOpensAt.Add(shift)
+	}
+	if msg := validateLifecycleDates(comingSoonAt, bookingOpensAt, releaseDate); msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+
 	// Start transaction
 	tx := database.DB.Begin()
 	defer func() {
@@ -278,6 +379,43 @@ func UpdateMovie(c *gin.Context) {
 	if req.IsActive != nil {
 		updateData["is_active"] = *req.IsActive
 	}
+	updateData["coming_soon_at"] = comingSoonAt
+	updateData["booking_opens_at"] = bookingOpensAt
+
+	// External IDs and origin; zero values clear them
+	if req.IMDbID != nil {
+		updateData["imdb_id"] = *req.IMDbID
+	}
+	if req.TMDBID != nil {
+		if *req.TMDBID == 0 {
+			updateData["tmdb_id"] = nil
+		} else {
+			updateData["tmdb_id"] = *req.TMDBID
+		}
+	}
+	if req.OriginalLanguageID != nil {
+		if *req.OriginalLanguageID == 0 {
+			updateData["original_language_id"] = nil
+		} else {
+			updateData["original_language_id"] = *req.OriginalLanguageID
+		}
+	}
+	if req.CountryOfOrigin != nil {
+		updateData["country_of_origin"] = strings.ToUpper(*req.CountryOfOrigin)
+	}
+
+	// A status set by hand sticks until the admin asks for automatic updates again
+	if req.Status != nil {
+		now := time.Now()
+		updateData["status"] = *req.Status
+		updateData["status_locked"] = true
+		updateData["status_changed_at"] = now
+		if models.MovieStatus(*req.Status) == models.MovieEnded {
+			updateData["ended_at"] = now
+		}
+	} else if req.AutoStatus {
+		updateData["status_locked"] = false
+	}
 
 	if len(updateData) > 0 {
 		if err := tx.Model(&movie).Updates(updateData).Error; err != nil {
@@ -302,21 +440,36 @@ func UpdateMovie(c *gin.Context) {
 		}
 	}
 
-	// Update cast if provided
-	if len(req.CastIDs) > 0 {
-		var persons []models.Person
-		if err := tx.Where("id IN ?", req.CastIDs).Find(&persons).Error; err != nil {
+	// Update cast and crew if provided. cast_ids only replaces the actors.
+	if len(req.Credits) > 0 {
+		credits, msg, err := resolveCredits(tx, movie.ID, req.Credits)
+		if msg != "" {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+			return
+		}
+		if err == nil {
+			err = replaceCredits(tx, movie.ID, credits)
+		}
+		if err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch cast"))
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update credits"))
 			return
 		}
-		if err := tx.Model(&movie).Association("Cast").Replace(&persons); err != nil {
+	} else if len(req.CastIDs) > 0 {
+		if err := replaceActors(tx, movie.ID, req.CastIDs); err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update cast"))
 			return
 		}
 	}
 
+	if err := refreshMovieStatus(tx, movie.ID); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update movie status"))
+		return
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update movie"))
@@ -324,7 +477,8 @@ func UpdateMovie(c *gin.Context) {
 	}
 
 	// Reload movie with associations
-	database.DB.Preload("Genres").Preload("Cast").First(&movie, movie.ID)
+	preloadCredits(database.DB.Preload("Genres").Preload("OriginalLanguage")).First(&movie, movie.ID)
+	movie.ArrangeCredits()
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Movie updated successfully", movie))
 }
@@ -363,7 +517,7 @@ func DeleteMovie(c *gin.Context) {
 		return
 	}
 
-	if err := tx.Model(&movie).Association("Cast").Clear(); err != nil {
+	if err := tx.Where("movie_id = ?", movie.ID).Delete(&models.MovieCast{}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to clear cast associations"))
 		return
@@ -384,3 +538,16 @@ func DeleteMovie(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Movie deleted successfully", nil))
 }
+
+// isCountryCode reports whether s looks like an ISO 3166-1 alpha-2 code
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
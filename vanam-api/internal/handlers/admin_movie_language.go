@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
 	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
 	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/storage"
 	"github.com/prabalesh/vanam/vanam-api/internal/utils"
 	"gorm.io/gorm"
 )
@@ -81,43 +83,6 @@ func GetMovieLanguages(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.SuccessResponse("Movie languages retrieved successfully", movieLanguages))
 }
 
-// GetMovieByLanguage - Get movie details in specific language
-func GetMovieByLanguage(c *gin.Context) {
-	movieID := c.Param("id")
-	languageCode := c.Query("lang")
-
-	if languageCode == "" {
-		languageCode = "en" // Default to English
-	}
-
-	id, err := strconv.Atoi(movieID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
-		return
-	}
-
-	var movie models.Movie
-	if err := database.DB.Preload("Genres").Preload("Cast").First(&movie, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.ErrorResponse("Movie not found"))
-		return
-	}
-
-	// Get localized content
-	var movieLanguage models.MovieLanguage
-	err = database.DB.Joins("JOIN languages ON languages.id = movie_languages.language_id").
-		Where("movie_languages.movie_id = ? AND languages.code = ?", id, languageCode).
-		First(&movieLanguage).Error
-
-	if err == nil {
-		// Return localized version
-		movie.OriginalTitle = movieLanguage.Title
-		movie.Description = movieLanguage.Description
-	}
-	// If no localization found, return original content
-
-	c.JSON(http.StatusOK, utils.SuccessResponse("Movie retrieved successfully", movie))
-}
-
 // UpdateMovieLanguage - Update language support for a movie
 func UpdateMovieLanguage(c *gin.Context) {
 	movieID := c.Param("id")
@@ -209,5 +174,11 @@ func RemoveMovieLanguage(c *gin.Context) {
 		return
 	}
 
+	if movieLanguage.SubtitleKey != "" {
+		if err := storage.Default.Delete(c.Request.Context(), movieLanguage.SubtitleKey); err != nil {
+			log.Printf("❌ Failed to delete subtitles %s: %v", movieLanguage.SubtitleKey, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, utils.SuccessResponse("Movie language removed successfully", nil))
 }
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetRatings - Get the default country's certifications as movie ratings, described in the
+// requested language (public endpoint). A rating without a description in any requested
+// language is described by its certification.
+func GetRatings(c *gin.Context) {
+	certifications, err := ratingCertifications(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch ratings"))
+		return
+	}
+
+	languages := requestLanguages(c)
+	descriptions := ratingTranslations(languages)
+
+	ratings := make([]gin.H, 0, len(certifications))
+	var served []string
+	seen := make(map[string]bool)
+	for _, certification := range certifications {
+		rating := models.MovieRating(certification.Code)
+		code := firstAvailable(languages, func(code string) bool {
+			_, ok := descriptions[rating][code]
+			return ok
+		})
+		label, text := certification.Name, certification.Description
+		if description, ok := descriptions[rating][code]; ok {
+			label, text = description.Label, description.Description
+		}
+
+		ratings = append(ratings, gin.H{
+			"rating":      rating,
+			"label":       label,
+			"description": text,
+			"language":    code,
+		})
+		if !seen[code] {
+			seen[code] = true
+			served = append(served, code)
+		}
+	}
+
+	c.Header("Content-Language", strings.Join(served, ", "))
+	c.JSON(http.StatusOK, utils.SuccessResponse("Ratings retrieved successfully", ratings))
+}
+
+// GetRatingDescriptions - Get all rating descriptions in every language (admin only)
+func GetRatingDescriptions(c *gin.Context) {
+	query := database.DB.Preload("Language")
+	if rating := c.Query("rating"); rating != "" {
+		query = query.Where("rating = ?", rating)
+	}
+	if languageID := c.Query("language_id"); languageID != "" {
+		query = query.Where("language_id = ?", languageID)
+	}
+
+	var descriptions []models.RatingDescription
+	if err := query.Order("rating ASC, language_id ASC").Find(&descriptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch rating descriptions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Rating descriptions retrieved successfully", descriptions))
+}
+
+// CreateRatingDescription - Describe a rating in one language (admin only)
+func CreateRatingDescription(c *gin.Context) {
+	var req dtos.RatingDescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if !checkRating(c, req.Rating) {
+		return
+	}
+
+	var language models.Language
+	if err := database.DB.First(&language, req.LanguageID).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Language not found"))
+		return
+	}
+
+	var existing models.RatingDescription
+	if err := database.DB.Where("rating = ? AND language_id = ?", req.Rating, req.LanguageID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Rating already has a description in this language"))
+		return
+	}
+
+	description := models.RatingDescription{
+		Rating:      models.MovieRating(req.Rating),
+		LanguageID:  req.LanguageID,
+		Label:       req.Label,
+		Description: req.Description,
+	}
+
+	if err := database.DB.Create(&description).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create rating description"))
+		return
+	}
+
+	description.Language = language
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Rating description created successfully", description))
+}
+
+// UpdateRatingDescription - Update a rating description (admin only)
+func UpdateRatingDescription(c *gin.Context) {
+	description, ok := loadRatingDescription(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.RatingDescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if !checkRating(c, req.Rating) {
+		return
+	}
+
+	var existing models.RatingDescription
+	if err := database.DB.Where("rating = ? AND language_id = ? AND id != ?", req.Rating, req.LanguageID, description.ID).
+		First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Rating already has a description in this language"))
+		return
+	}
+
+	updateData := map[string]interface{}{
+		"rating":      req.Rating,
+		"language_id": req.LanguageID,
+		"label":       req.Label,
+		"description": req.Description,
+	}
+
+	if err := database.DB.Model(&description).Updates(updateData).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update rating description"))
+		return
+	}
+
+	// Reload with language info
+	database.DB.Preload("Language").First(&description, description.ID)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Rating description updated successfully", description))
+}
+
+// DeleteRatingDescription - Delete a rating description (admin only)
+func DeleteRatingDescription(c *gin.Context) {
+	description, ok := loadRatingDescription(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(&description).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete rating description"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Rating description deleted successfully", nil))
+}
+
+func loadRatingDescription(c *gin.Context) (models.RatingDescription, bool) {
+	var description models.RatingDescription
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid rating description ID"))
+		return description, false
+	}
+
+	if err := database.DB.First(&description, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Rating description not found"))
+			return description, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return description, false
+	}
+
+	return description, true
+}
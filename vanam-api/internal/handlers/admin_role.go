@@ -29,7 +29,8 @@ func CreateRole(c *gin.Context) {
 
 	// Create role
 	role := models.Role{
-		Name: req.Name,
+		Name:             req.Name,
+		RequireTwoFactor: req.RequireTwoFactor,
 	}
 
 	if err := database.DB.Create(&role).Error; err != nil {
@@ -39,8 +40,9 @@ func CreateRole(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, utils.SuccessResponse("Role created successfully", gin.H{
 		"role": gin.H{
-			"id":   role.ID,
-			"name": role.Name,
+			"id":                 role.ID,
+			"name":               role.Name,
+			"require_two_factor": role.RequireTwoFactor,
 		},
 	}))
 }
@@ -76,8 +78,9 @@ func GetAllRoles(c *gin.Context) {
 	var roleList []gin.H
 	for _, role := range roles {
 		roleList = append(roleList, gin.H{
-			"id":   role.ID,
-			"name": role.Name,
+			"id":                 role.ID,
+			"name":               role.Name,
+			"require_two_factor": role.RequireTwoFactor,
 		})
 	}
 
@@ -105,8 +108,9 @@ func GetRoleByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Role retrieved successfully", gin.H{
 		"role": gin.H{
-			"id":   role.ID,
-			"name": role.Name,
+			"id":                 role.ID,
+			"name":               role.Name,
+			"require_two_factor": role.RequireTwoFactor,
 		},
 	}))
 }
@@ -147,6 +151,10 @@ func UpdateRole(c *gin.Context) {
 		role.Name = req.Name
 	}
 
+	if req.RequireTwoFactor != nil {
+		role.RequireTwoFactor = *req.RequireTwoFactor
+	}
+
 	// Update role
 	if err := database.DB.Save(&role).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update role"))
@@ -155,8 +163,9 @@ func UpdateRole(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Role updated successfully", gin.H{
 		"role": gin.H{
-			"id":   role.ID,
-			"name": role.Name,
+			"id":                 role.ID,
+			"name":               role.Name,
+			"require_two_factor": role.RequireTwoFactor,
 		},
 	}))
 }
@@ -111,23 +111,7 @@ func CreateScreen(c *gin.Context) {
 	}
 
 	// Create individual seats
-	var seats []models.Seat
-	for _, row := range req.SeatLayout.Layout {
-		for _, seatPos := range row {
-			if seatPos.Type != "walkway" && seatPos.Type != "empty" {
-				seat := models.Seat{
-					ScreenID:   screen.ID,
-					SeatNumber: seatPos.Number,
-					Row:        seatPos.Row,
-					Column:     seatPos.Column,
-					SeatType:   seatPos.Type,
-					Status:     "available",
-					Price:      seatPos.Price,
-				}
-				seats = append(seats, seat)
-			}
-		}
-	}
+	seats := seatsFromLayout(screen.ID, req.SeatLayout)
 
 	if len(seats) > 0 {
 		if err := tx.CreateInBatches(seats, 100).Error; err != nil {
@@ -208,23 +192,7 @@ func UpdateScreen(c *gin.Context) {
 	}
 
 	// Create new seats
-	var seats []models.Seat
-	for _, row := range req.SeatLayout.Layout {
-		for _, seatPos := range row {
-			if seatPos.Type != "walkway" && seatPos.Type != "empty" {
-				seat := models.Seat{
-					ScreenID:   screen.ID,
-					SeatNumber: seatPos.Number,
-					Row:        seatPos.Row,
-					Column:     seatPos.Column,
-					SeatType:   seatPos.Type,
-					Status:     "available",
-					Price:      seatPos.Price,
-				}
-				seats = append(seats, seat)
-			}
-		}
-	}
+	seats := seatsFromLayout(screen.ID, req.SeatLayout)
 
 	if len(seats) > 0 {
 		if err := tx.CreateInBatches(seats, 100).Error; err != nil {
@@ -317,3 +285,36 @@ func GetAllScreens(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Screens retrieved successfully", response))
 }
+
+// seatsFromLayout builds the seat rows for a screen from its layout designer grid
+func seatsFromLayout(screenID uint, layout models.SeatLayoutConfig) []models.Seat {
+	accessibleSeats := make(map[string]bool)
+	for _, number := range layout.AccessibleSeats {
+		accessibleSeats[number] = true
+	}
+
+	var seats []models.Seat
+	for _, row := range layout.Layout {
+		for _, seatPos := range row {
+			if seatPos.Type != "walkway" && seatPos.Type != "empty" {
+				// A seat is accessible if the cell, the accessible list or its seat type says so
+				isAccessible := seatPos.IsAccessible || accessibleSeats[seatPos.Number] ||
+					layout.SeatTypes[seatPos.Type].IsAccessible || seatPos.Type == "disabled_access"
+
+				seat := models.Seat{
+					ScreenID:     screenID,
+					SeatNumber:   seatPos.Number,
+					Row:          seatPos.Row,
+					Column:       seatPos.Column,
+					SeatType:     seatPos.Type,
+					Status:       "available",
+					Price:        seatPos.Price,
+					IsAccessible: isAccessible,
+				}
+				seats = append(seats, seat)
+			}
+		}
+	}
+
+	return seats
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
@@ -83,6 +84,10 @@ func CreateScreening(c *gin.Context) {
 		return
 	}
 
+	if err := refreshMovieStatus(database.DB, screening.MovieID); err != nil {
+		log.Printf("❌ Failed to refresh status of movie %d: %v", screening.MovieID, err)
+	}
+
 	// Load relationships
 	database.DB.Preload("Movie").Preload("Screen.Theater").Preload("Language").Preload("SubtitleLanguage").First(&screening, screening.ID)
 
@@ -127,6 +132,17 @@ func GetScreenings(c *gin.Context) {
 		query = query.Where("screen_id = ?", *filters.ScreenID)
 	}
 
+	if filters.Accessible != nil && *filters.Accessible {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM seats
+			WHERE seats.screen_id = screenings.screen_id AND seats.is_accessible = ? AND seats.status <> ?
+			AND NOT EXISTS (
+				SELECT 1 FROM tickets
+				WHERE tickets.seat_id = seats.id AND tickets.screening_id = screenings.id AND tickets.status <> ?
+			)
+		)`, true, "blocked", models.TicketCancelled)
+	}
+
 	// Only active screenings with available seats
 	query = query.Where("screenings.is_active = ? AND available_seats > 0", true)
 
@@ -143,6 +159,11 @@ func GetScreenings(c *gin.Context) {
 		return
 	}
 
+	if err := attachAccessibilityCounts(screenings); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch accessible seat counts"))
+		return
+	}
+
 	c.JSON(http.StatusOK, utils.PaginationResponse("Screenings retrieved successfully", screenings, page, limit, total))
 }
 
@@ -165,7 +186,13 @@ func GetScreeningByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Screening retrieved successfully", screening))
+	screenings := []models.Screening{screening}
+	if err := attachAccessibilityCounts(screenings); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch accessible seat counts"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Screening retrieved successfully", screenings[0]))
 }
 
 // UpdateScreening - Update screening
@@ -233,6 +260,11 @@ func UpdateScreening(c *gin.Context) {
 		updateData["video_format"] = req.VideoFormat
 	}
 	if req.IsActive != nil {
+		// Its customers have been refunded or offered a rebook, so it can't be sold again
+		if *req.IsActive && screening.Status == models.ScreeningCancelled {
+			c.JSON(http.StatusConflict, utils.ErrorResponse("Screening is cancelled and can't be reactivated"))
+			return
+		}
 		updateData["is_active"] = *req.IsActive
 	}
 
@@ -241,6 +273,10 @@ func UpdateScreening(c *gin.Context) {
 		return
 	}
 
+	if err := refreshMovieStatus(database.DB, screening.MovieID); err != nil {
+		log.Printf("❌ Failed to refresh status of movie %d: %v", screening.MovieID, err)
+	}
+
 	// Reload screening
 	database.DB.Preload("Movie").Preload("Screen.Theater").Preload("Language").Preload("SubtitleLanguage").First(&screening, screening.ID)
 
@@ -266,11 +302,25 @@ func DeleteScreening(c *gin.Context) {
 		return
 	}
 
+	// Screenings people hold tickets for must go through the cancel flow instead
+	var bookingCount int64
+	database.DB.Model(&models.Booking{}).
+		Where("screening_id = ? AND status IN ?", screening.ID, []models.BookingStatus{models.BookingConfirmed, models.BookingRebookOffered}).
+		Count(&bookingCount)
+	if bookingCount > 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Screening has bookings, cancel it instead"))
+		return
+	}
+
 	if err := database.DB.Delete(&screening).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete screening"))
 		return
 	}
 
+	if err := refreshMovieStatus(database.DB, screening.MovieID); err != nil {
+		log.Printf("❌ Failed to refresh status of movie %d: %v", screening.MovieID, err)
+	}
+
 	c.JSON(http.StatusOK, utils.SuccessResponse("Screening deleted successfully", nil))
 }
 
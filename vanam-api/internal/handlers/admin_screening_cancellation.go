@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const defaultRebookOfferHours = 72
+
+type screeningImpactReport struct {
+	ScreeningID       uint                  `json:"screening_id"`
+	Movie             string                `json:"movie"`
+	ShowStartsAt      time.Time             `json:"show_starts_at"`
+	Reason            string                `json:"reason"`
+	Mode              string                `json:"mode"`
+	BookingsAffected  int                   `json:"bookings_affected"`
+	TicketsAffected   int                   `json:"tickets_affected"`
+	CustomersNotified int                   `json:"customers_notified"`
+	RefundsIssued     int                   `json:"refunds_issued"`
+	RefundTotal       float64               `json:"refund_total"`
+	RebookOffers      int                   `json:"rebook_offers"`
+	Failures          int                   `json:"failures"`
+	Retry             bool                  `json:"retry,omitempty"` // Only covers bookings an earlier run failed on
+	Bookings          []bookingImpactResult `json:"bookings"`
+}
+
+type bookingImpactResult struct {
+	BookingID    uint    `json:"booking_id"`
+	BookingRef   string  `json:"booking_ref"`
+	UserID       uint    `json:"user_id"`
+	Seats        int     `json:"seats"`
+	Action       string  `json:"action"` // refunded, rebook_offered, failed
+	RefundAmount float64 `json:"refund_amount,omitempty"`
+	Notified     bool    `json:"notified"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// CancelScreening - Cancel a show and refund or offer rebooking to every booking for it.
+// Cancelling an already cancelled show retries the bookings that are still confirmed.
+func CancelScreening(c *gin.Context) {
+	screeningID := c.Param("id")
+	id, err := strconv.ParseUint(screeningID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid screening ID"))
+		return
+	}
+
+	var req dtos.CancelScreeningRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = "refund"
+	}
+	if req.OfferHours == 0 {
+		req.OfferHours = defaultRebookOfferHours
+	}
+
+	var screening models.Screening
+	if err := database.DB.Preload("Movie").First(&screening, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Screening not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	// Mark the screening cancelled first so no new bookings can land while we cascade.
+	// Bookings a failed run left confirmed are picked up by cancelling again.
+	retry := screening.Status == models.ScreeningCancelled
+	if !retry {
+		now := time.Now()
+		if err := database.DB.Model(&screening).Updates(map[string]interface{}{
+			"status":              models.ScreeningCancelled,
+			"is_active":           false,
+			"cancelled_at":        now,
+			"cancellation_reason": req.Reason,
+		}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to cancel screening"))
+			return
+		}
+	}
+
+	var bookings []models.Booking
+	if err := database.DB.Where("screening_id = ? AND status = ?", screening.ID, models.BookingConfirmed).
+		Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch bookings"))
+		return
+	}
+	if retry && len(bookings) == 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Screening is already cancelled"))
+		return
+	}
+
+	report := screeningImpactReport{
+		ScreeningID:      screening.ID,
+		Movie:            screening.Movie.OriginalTitle,
+		ShowStartsAt:     screening.StartsAt(),
+		Reason:           req.Reason,
+		Mode:             req.Mode,
+		BookingsAffected: len(bookings),
+		Retry:            retry,
+	}
+
+	adminID := c.GetUint("user_id")
+	showLabel := fmt.Sprintf("%s on %s", screening.Movie.OriginalTitle, screening.StartsAt().Format("Mon 02 Jan 15:04"))
+
+	for _, booking := range bookings {
+		result := bookingImpactResult{
+			BookingID:  booking.ID,
+			BookingRef: booking.BookingRef,
+			UserID:     booking.UserID,
+			Seats:      booking.SeatCount,
+		}
+		report.TicketsAffected += booking.SeatCount
+
+		var subject, body string
+		if req.Mode == "rebook" {
+			offer, err := offerRebook(booking, time.Duration(req.OfferHours)*time.Hour)
+			if err != nil {
+				result.Action = "failed"
+				result.Error = err.Error()
+				report.Failures++
+				report.Bookings = append(report.Bookings, result)
+				continue
+			}
+			result.Action = "rebook_offered"
+			report.RebookOffers++
+
+			subject = "Your show has been cancelled: " + showLabel
+			body = fmt.Sprintf("We're sorry, %s has been cancelled (%s). You can move booking %s to another show or take a full refund until %s.",
+				showLabel, req.Reason, booking.BookingRef, offer.ExpiresAt.Format("Mon 02 Jan 15:04"))
+		} else {
+			cancellation, _, err := cancelBooking(booking.ID, cancelOptions{
+				CancelledBy:     adminID,
+				Forced:          true,
+				Reason:          "Screening cancelled: " + req.Reason,
+				HoursBeforeShow: time.Until(screening.StartsAt()).Hours(),
+				RefundPercent:   100,
+			})
+			if err != nil {
+				result.Action = "failed"
+				result.Error = err.Error()
+				report.Failures++
+				report.Bookings = append(report.Bookings, result)
+				continue
+			}
+			result.Action = "refunded"
+			result.RefundAmount = cancellation.RefundAmount
+			report.RefundsIssued++
+			report.RefundTotal += cancellation.RefundAmount
+
+			subject = "Your show has been cancelled: " + showLabel
+			body = fmt.Sprintf("We're sorry, %s has been cancelled (%s). Booking %s has been refunded in full (%.2f).",
+				showLabel, req.Reason, booking.BookingRef, cancellation.RefundAmount)
+		}
+
+		if err := queueNotification(booking.UserID, subject, body); err == nil {
+			result.Notified = true
+			report.CustomersNotified++
+		}
+
+		report.Bookings = append(report.Bookings, result)
+	}
+
+	if err := refreshMovieStatus(database.DB, screening.MovieID); err != nil {
+		log.Printf("❌ Failed to refresh status of movie %d: %v", screening.MovieID, err)
+	}
+
+	reportJSON, _ := json.Marshal(report)
+	record := models.ScreeningCancellation{
+		ScreeningID: screening.ID,
+		CancelledBy: adminID,
+		Reason:      req.Reason,
+		Mode:        req.Mode,
+		Report:      string(reportJSON),
+	}
+	var saveErr error
+	if retry {
+		// The report of a retry replaces the earlier one, keeping who cancelled and why
+		saveErr = database.DB.Model(&models.ScreeningCancellation{}).Where("screening_id = ?", screening.ID).
+			Updates(map[string]interface{}{"mode": req.Mode, "report": record.Report}).Error
+	} else {
+		saveErr = database.DB.Create(&record).Error
+	}
+	if saveErr != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Screening cancelled but failed to save impact report"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Screening cancelled successfully", report))
+}
+
+// GetScreeningCancellation - Get the impact report of a cancelled screening
+func GetScreeningCancellation(c *gin.Context) {
+	screeningID := c.Param("id")
+	id, err := strconv.ParseUint(screeningID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid screening ID"))
+		return
+	}
+
+	var record models.ScreeningCancellation
+	if err := database.DB.Where("screening_id = ?", id).First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Screening has not been cancelled"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Screening cancellation retrieved successfully", gin.H{
+		"id":           record.ID,
+		"screening_id": record.ScreeningID,
+		"cancelled_by": record.CancelledBy,
+		"reason":       record.Reason,
+		"mode":         record.Mode,
+		"report":       json.RawMessage(record.Report),
+		"created_at":   record.CreatedAt,
+	}))
+}
+
+// offerRebook voids a booking's tickets and opens a rebook offer for it
+func offerRebook(booking models.Booking, validFor time.Duration) (*models.RebookOffer, error) {
+	offer := models.RebookOffer{
+		BookingID:   booking.ID,
+		ScreeningID: booking.ScreeningID,
+		Status:      models.RebookOfferOpen,
+		ExpiresAt:   time.Now().Add(validFor),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Ticket{}).
+			Where("booking_id = ? AND status <> ?", booking.ID, models.TicketCancelled).
+			Update("status", models.TicketCancelled).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&booking).Update("status", models.BookingRebookOffered).Error; err != nil {
+			return err
+		}
+		return tx.Create(&offer).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &offer, nil
+}
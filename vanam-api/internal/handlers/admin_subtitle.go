@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/i18n"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/storage"
+	"github.com/prabalesh/vanam/vanam-api/internal/subtitles"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const maxSubtitleBytes = 2 << 20
+
+// UploadSubtitles - Upload and validate the subtitle file of a movie language. With
+// dry_run=true the file is only checked.
+func UploadSubtitles(c *gin.Context) {
+	movieLanguage, ok := loadMovieLanguage(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("file is required"))
+		return
+	}
+	if fileHeader.Size > maxSubtitleBytes {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Subtitle file is too large"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read file"))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxSubtitleBytes))
+	file.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read file"))
+		return
+	}
+
+	data, problems := subtitles.CheckEncoding(data)
+	report := gin.H{"problems": problems}
+	if subtitles.HasErrors(problems) {
+		rejectSubtitles(c, report)
+		return
+	}
+
+	format := subtitles.DetectFormat(data)
+	cues, parseProblems := subtitles.Parse(data, format)
+	problems = append(problems, parseProblems...)
+	problems = append(problems, subtitles.Validate(cues, time.Duration(movieLanguage.Movie.Duration)*time.Minute)...)
+
+	report = gin.H{
+		"format":   format,
+		"cues":     len(cues),
+		"problems": problems,
+	}
+	if len(cues) > 0 {
+		report["last_cue_ends_at"] = cues[len(cues)-1].End.String()
+	}
+	if subtitles.HasErrors(problems) {
+		rejectSubtitles(c, report)
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		c.JSON(http.StatusOK, utils.SuccessResponse("Subtitle file is valid", report))
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("subtitles/%d/%s.%s", movieLanguage.MovieID, strings.ToLower(movieLanguage.Language.Code), format)
+	if err := storage.Default.Put(ctx, key, data, format.ContentType()); err != nil {
+		log.Printf("❌ Failed to store subtitles %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to store subtitle file"))
+		return
+	}
+
+	previousKey := movieLanguage.SubtitleKey
+	now := time.Now()
+	if err := database.DB.Model(&movieLanguage).Updates(map[string]interface{}{
+		"has_subtitles":        true,
+		"subtitle_format":      strings.ToUpper(string(format)),
+		"subtitle_key":         key,
+		"subtitle_cue_count":   len(cues),
+		"subtitle_uploaded_at": now,
+	}).Error; err != nil {
+		// Remove the file nothing points at. The same key is still the current file.
+		if key != previousKey {
+			if err := storage.Default.Delete(ctx, key); err != nil {
+				log.Printf("❌ Failed to delete subtitles %s: %v", key, err)
+			}
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update movie language"))
+		return
+	}
+
+	// A file in the other format is replaced rather than overwritten
+	if previousKey != "" && previousKey != key {
+		if err := storage.Default.Delete(ctx, previousKey); err != nil {
+			log.Printf("❌ Failed to delete old subtitles %s: %v", previousKey, err)
+		}
+	}
+
+	// Reload with language info
+	var updated models.MovieLanguage
+	database.DB.Preload("Language").First(&updated, movieLanguage.ID)
+
+	report["movie_language"] = updated
+	c.JSON(http.StatusOK, utils.SuccessResponse("Subtitles uploaded successfully", report))
+}
+
+// DeleteSubtitles - Remove the subtitle file of a movie language
+func DeleteSubtitles(c *gin.Context) {
+	movieLanguage, ok := loadMovieLanguage(c)
+	if !ok {
+		return
+	}
+
+	if movieLanguage.SubtitleKey == "" {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Movie language has no subtitle file"))
+		return
+	}
+
+	if err := storage.Default.Delete(c.Request.Context(), movieLanguage.SubtitleKey); err != nil {
+		log.Printf("❌ Failed to delete subtitles %s: %v", movieLanguage.SubtitleKey, err)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete subtitle file"))
+		return
+	}
+
+	if err := database.DB.Model(&movieLanguage).Updates(map[string]interface{}{
+		"has_subtitles":        false,
+		"subtitle_key":         "",
+		"subtitle_cue_count":   0,
+		"subtitle_uploaded_at": nil,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update movie language"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Subtitles deleted successfully", nil))
+}
+
+// GetSubtitles - Download a movie's subtitles in one language, converted with ?format=srt|vtt
+func GetSubtitles(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
+		return
+	}
+	code := i18n.Normalize(c.Param("lang"))
+
+	var movieLanguage models.MovieLanguage
+	if err := database.DB.Joins("JOIN languages ON languages.id = movie_languages.language_id").
+		Where("movie_languages.movie_id = ? AND LOWER(languages.code) = ? AND movie_languages.subtitle_key <> ''", movieID, code).
+		First(&movieLanguage).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Subtitles not found"))
+		return
+	}
+
+	stored, _ := subtitles.ParseFormat(movieLanguage.SubtitleFormat)
+	format := stored
+	if requested := c.Query("format"); requested != "" {
+		var ok bool
+		if format, ok = subtitles.ParseFormat(requested); !ok {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("format must be srt or vtt"))
+			return
+		}
+	}
+
+	data, err := storage.Default.Get(c.Request.Context(), movieLanguage.SubtitleKey)
+	if err != nil {
+		log.Printf("❌ Failed to read subtitles %s: %v", movieLanguage.SubtitleKey, err)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to read subtitle file"))
+		return
+	}
+
+	if format != stored {
+		cues, _ := subtitles.Parse(data, stored)
+		data = subtitles.Encode(cues, format)
+	}
+
+	c.Header("Content-Language", code)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="movie-%d-%s.%s"`, movieID, code, format))
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+func rejectSubtitles(c *gin.Context, report gin.H) {
+	response := utils.ErrorResponse("Subtitle file has errors")
+	response["data"] = report
+	c.JSON(http.StatusUnprocessableEntity, response)
+}
+
+// loadMovieLanguage finds the movie language addressed by :id and :langId, responding if it can't
+func loadMovieLanguage(c *gin.Context) (models.MovieLanguage, bool) {
+	var movieLanguage models.MovieLanguage
+
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid movie ID"))
+		return movieLanguage, false
+	}
+
+	langID, err := strconv.Atoi(c.Param("langId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid language ID"))
+		return movieLanguage, false
+	}
+
+	if err := database.DB.Preload("Movie").Preload("Language").
+		Where("movie_id = ? AND language_id = ?", movieID, langID).
+		First(&movieLanguage).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Movie language not found"))
+			return movieLanguage, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return movieLanguage, false
+	}
+
+	return movieLanguage, true
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
@@ -92,6 +93,7 @@ func CreateTheater(c *gin.Context) {
 		Address:  req.Address,
 		City:     req.City,
 		State:    req.State,
+		Country:  strings.ToUpper(req.Country),
 		IsActive: true, // Default to active
 	}
 
@@ -137,6 +139,7 @@ func UpdateTheater(c *gin.Context) {
 	theater.Address = req.Address
 	theater.City = req.City
 	theater.State = req.State
+	theater.Country = strings.ToUpper(req.Country)
 
 	if req.IsActive != nil {
 		theater.IsActive = *req.IsActive
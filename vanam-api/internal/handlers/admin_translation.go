@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/i18n"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/translations"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxTranslationImportBytes = 10 << 20
+
+// ExportTranslations - Export movie and genre translations for one language as CSV or XLIFF
+func ExportTranslations(c *gin.Context) {
+	code := i18n.Normalize(c.Query("lang"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("lang is required"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xliff" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("format must be csv or xliff"))
+		return
+	}
+
+	var language models.Language
+	if err := database.DB.Where("LOWER(code) = ?", code).First(&language).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Language not found"))
+		return
+	}
+
+	units, err := translationUnits(language, c.Query("missing_only") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to load translations"))
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	if format == "xliff" {
+		contentType, extension = "application/x-xliff+xml; charset=utf-8", "xlf"
+		err = translations.WriteXLIFF(&buf, config.App.DefaultLanguage, language.Code, units)
+	} else {
+		err = translations.WriteCSV(&buf, units)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to export translations"))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="translations-%s.%s"`, language.Code, extension))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// ImportTranslations - Preview or apply a CSV or XLIFF translation file. With dry_run=true
+// only the diff is returned. Otherwise the whole file is applied in one transaction, or
+// not at all if it has errors, or conflicts and force isn't set.
+func ImportTranslations(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("file is required"))
+		return
+	}
+	if fileHeader.Size > maxTranslationImportBytes {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("File is too large"))
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+		case ".csv":
+			format = "csv"
+		case ".xlf", ".xliff":
+			format = "xliff"
+		}
+	}
+	if format != "csv" && format != "xliff" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("format must be csv or xliff"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read file"))
+		return
+	}
+	defer file.Close()
+
+	var units []translations.Unit
+	var issues []translations.Issue
+	if format == "xliff" {
+		units, issues, err = translations.ReadXLIFF(file)
+	} else {
+		units, issues, err = translations.ReadCSV(file)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	plan, err := planTranslationImport(units, issues)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to check translations"))
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		c.JSON(http.StatusOK, utils.SuccessResponse("Translation import preview", plan.report))
+		return
+	}
+
+	if len(plan.report.Errors) > 0 || (len(plan.report.Conflicts) > 0 && c.Query("force") != "true") {
+		response := utils.ErrorResponse("Translation import has errors or conflicts, nothing was applied")
+		response["data"] = plan.report
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err := database.DB.Transaction(plan.apply); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to apply translations"))
+		return
+	}
+	plan.report.Applied = true
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Translations imported successfully", plan.report))
+}
+
+// translationUnits lists every translatable field in the catalog with its translation
+// into language, or only the untranslated ones
+func translationUnits(language models.Language, missingOnly bool) ([]translations.Unit, error) {
+	var movies []models.Movie
+	if err := database.DB.Order("id ASC").Find(&movies).Error; err != nil {
+		return nil, err
+	}
+	var movieLanguages []models.MovieLanguage
+	if err := database.DB.Where("language_id = ?", language.ID).Find(&movieLanguages).Error; err != nil {
+		return nil, err
+	}
+	movieTranslations := make(map[uint]models.MovieLanguage)
+	for _, ml := range movieLanguages {
+		movieTranslations[ml.MovieID] = ml
+	}
+
+	var genres []models.Genre
+	if err := database.DB.Order("id ASC").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+	var genreLanguages []models.GenreLanguage
+	if err := database.DB.Where("language_id = ?", language.ID).Find(&genreLanguages).Error; err != nil {
+		return nil, err
+	}
+	genreTranslations := make(map[uint]string)
+	for _, gl := range genreLanguages {
+		genreTranslations[gl.GenreID] = gl.Name
+	}
+
+	units := []translations.Unit{}
+	add := func(key translations.Key, source, target string) {
+		missing := target == ""
+		if missingOnly && !missing {
+			return
+		}
+		units = append(units, translations.Unit{Key: key, Language: language.Code, Source: source, Target: target, Missing: missing})
+	}
+
+	for _, movie := range movies {
+		translation := movieTranslations[movie.ID]
+		add(translations.Key{Kind: translations.KindMovie, ID: movie.ID, Field: translations.FieldTitle}, movie.OriginalTitle, translation.Title)
+		if movie.Description != "" || translation.Description != "" {
+			add(translations.Key{Kind: translations.KindMovie, ID: movie.ID, Field: translations.FieldDescription}, movie.Description, translation.Description)
+		}
+	}
+	for _, genre := range genres {
+		add(translations.Key{Kind: translations.KindGenre, ID: genre.ID, Field: translations.FieldName}, genre.Name, genreTranslations[genre.ID])
+	}
+
+	return units, nil
+}
+
+type translationRecordKey struct {
+	id         uint
+	languageID uint
+}
+
+// translationImport is a checked import: the report plus the records it would write
+type translationImport struct {
+	report dtos.TranslationImportReport
+
+	movies      map[translationRecordKey]*models.MovieLanguage
+	genres      map[translationRecordKey]*models.GenreLanguage
+	dirtyMovies []*models.MovieLanguage
+	dirtyGenres []*models.GenreLanguage
+}
+
+// planTranslationImport checks units against the catalog and works out what applying
+// them would change
+func planTranslationImport(units []translations.Unit, issues []translations.Issue) (*translationImport, error) {
+	plan := &translationImport{
+		report: dtos.TranslationImportReport{
+			Units:     len(units),
+			Changes:   []dtos.TranslationChange{},
+			Conflicts: []translations.Issue{},
+			Errors:    append([]translations.Issue{}, issues...),
+		},
+		movies: make(map[translationRecordKey]*models.MovieLanguage),
+		genres: make(map[translationRecordKey]*models.GenreLanguage),
+	}
+
+	var languages []models.Language
+	if err := database.DB.Find(&languages).Error; err != nil {
+		return nil, err
+	}
+	languagesByCode := make(map[string]models.Language)
+	languageCodes := make(map[uint]string)
+	for _, language := range languages {
+		languagesByCode[i18n.Normalize(language.Code)] = language
+		languageCodes[language.ID] = language.Code
+	}
+
+	var movieIDs, genreIDs []uint
+	for _, unit := range units {
+		if unit.Key.Kind == translations.KindMovie {
+			movieIDs = append(movieIDs, unit.Key.ID)
+		} else {
+			genreIDs = append(genreIDs, unit.Key.ID)
+		}
+	}
+
+	movies := make(map[uint]models.Movie)
+	genres := make(map[uint]models.Genre)
+	if len(movieIDs) > 0 {
+		var found []models.Movie
+		if err := database.DB.Where("id IN ?", movieIDs).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		for _, movie := range found {
+			movies[movie.ID] = movie
+		}
+
+		var existing []models.MovieLanguage
+		if err := database.DB.Where("movie_id IN ?", movieIDs).Find(&existing).Error; err != nil {
+			return nil, err
+		}
+		for i := range existing {
+			plan.movies[translationRecordKey{existing[i].MovieID, existing[i].LanguageID}] = &existing[i]
+		}
+	}
+	if len(genreIDs) > 0 {
+		var found []models.Genre
+		if err := database.DB.Where("id IN ?", genreIDs).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		for _, genre := range found {
+			genres[genre.ID] = genre
+		}
+
+		var existing []models.GenreLanguage
+		if err := database.DB.Where("genre_id IN ?", genreIDs).Find(&existing).Error; err != nil {
+			return nil, err
+		}
+		for i := range existing {
+			plan.genres[translationRecordKey{existing[i].GenreID, existing[i].LanguageID}] = &existing[i]
+		}
+	}
+
+	seen := make(map[string]string)
+	dirty := make(map[interface{}]bool)
+	for _, unit := range units {
+		key := unit.Key.String()
+		issue := translations.Issue{Line: unit.Line, Key: key, Language: unit.Language}
+
+		language, ok := languagesByCode[i18n.Normalize(unit.Language)]
+		if !ok {
+			issue.Message = fmt.Sprintf("unknown language %q", unit.Language)
+			plan.report.Errors = append(plan.report.Errors, issue)
+			continue
+		}
+
+		target := strings.TrimSpace(unit.Target)
+		seenKey := key + "@" + i18n.Normalize(unit.Language)
+		if previous, ok := seen[seenKey]; ok {
+			if previous != target {
+				issue.Message = "appears more than once with different translations"
+				plan.report.Conflicts = append(plan.report.Conflicts, issue)
+			}
+			continue
+		}
+		seen[seenKey] = target
+
+		if target == "" {
+			plan.report.Skipped++
+			continue
+		}
+
+		var source string
+		var field *string
+		var record interface{}
+		recordKey := translationRecordKey{unit.Key.ID, language.ID}
+		switch unit.Key.Kind {
+		case translations.KindMovie:
+			movie, ok := movies[unit.Key.ID]
+			if !ok {
+				issue.Message = fmt.Sprintf("unknown movie ID %d", unit.Key.ID)
+				plan.report.Errors = append(plan.report.Errors, issue)
+				continue
+			}
+			translation := plan.movies[recordKey]
+			if translation == nil {
+				translation = &models.MovieLanguage{MovieID: movie.ID, LanguageID: language.ID}
+				plan.movies[recordKey] = translation
+			}
+			source, field, record = movie.OriginalTitle, &translation.Title, translation
+			if unit.Key.Field == translations.FieldDescription {
+				source, field = movie.Description, &translation.Description
+			}
+		case translations.KindGenre:
+			genre, ok := genres[unit.Key.ID]
+			if !ok {
+				issue.Message = fmt.Sprintf("unknown genre ID %d", unit.Key.ID)
+				plan.report.Errors = append(plan.report.Errors, issue)
+				continue
+			}
+			translation := plan.genres[recordKey]
+			if translation == nil {
+				translation = &models.GenreLanguage{GenreID: genre.ID, LanguageID: language.ID}
+				plan.genres[recordKey] = translation
+			}
+			source, field, record = genre.Name, &translation.Name, translation
+		}
+
+		// The source column is what the translator saw; if it no longer matches, the
+		// translation may be of outdated text
+		if strings.TrimSpace(unit.Source) != "" && strings.TrimSpace(unit.Source) != strings.TrimSpace(source) {
+			issue.Message = "source text has changed since export"
+			plan.report.Conflicts = append(plan.report.Conflicts, issue)
+		}
+
+		if *field == target {
+			plan.report.Unchanged++
+			continue
+		}
+
+		change := dtos.TranslationChange{Key: key, Language: language.Code, Action: "update", Old: *field, New: target}
+		if *field == "" {
+			change.Action = "create"
+			plan.report.Created++
+		} else {
+			plan.report.Updated++
+		}
+		plan.report.Changes = append(plan.report.Changes, change)
+		*field = target
+
+		if !dirty[record] {
+			dirty[record] = true
+			switch r := record.(type) {
+			case *models.MovieLanguage:
+				plan.dirtyMovies = append(plan.dirtyMovies, r)
+			case *models.GenreLanguage:
+				plan.dirtyGenres = append(plan.dirtyGenres, r)
+			}
+		}
+	}
+
+	// Movie translations can't exist without a title
+	for _, translation := range plan.dirtyMovies {
+		if translation.ID == 0 && translation.Title == "" {
+			key := translations.Key{Kind: translations.KindMovie, ID: translation.MovieID, Field: translations.FieldTitle}
+			plan.report.Errors = append(plan.report.Errors, translations.Issue{
+				Key:      key.String(),
+				Language: languageCodes[translation.LanguageID],
+				Message:  "a new movie translation needs a title",
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// apply writes the planned changes, for use inside a transaction
+func (plan *translationImport) apply(tx *gorm.DB) error {
+	for _, translation := range plan.dirtyMovies {
+		if err := tx.Omit(clause.Associations).Save(translation).Error; err != nil {
+			return err
+		}
+	}
+	for _, translation := range plan.dirtyGenres {
+		if err := tx.Omit(clause.Associations).Save(translation).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
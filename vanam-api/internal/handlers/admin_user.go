@@ -1,17 +1,21 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
 	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/i18n"
 	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+	"github.com/prabalesh/vanam/vanam-api/internal/tokens"
 	"github.com/prabalesh/vanam/vanam-api/internal/utils"
-	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
 	"gorm.io/gorm"
 )
 
@@ -30,6 +34,10 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	if rejectWeakPassword(c, "password", req.Password, models.User{Email: req.Email, Name: req.Name}) {
+		return
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -45,12 +53,15 @@ func CreateUser(c *gin.Context) {
 	}
 
 	// Create user
+	now := time.Now()
 	user := models.User{
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: hashedPassword,
 		RoleID:   req.RoleID,
 		IsActive: true,
+
+		PasswordChangedAt: &now,
 	}
 
 	if err := database.DB.Create(&user).Error; err != nil {
@@ -58,17 +69,22 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	if err := sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
 	// Load role for response
 	database.DB.Preload("Role").First(&user, user.ID)
 
 	c.JSON(http.StatusCreated, utils.SuccessResponse("User created successfully", gin.H{
 		"user": gin.H{
-			"id":         user.ID,
-			"name":       user.Name,
-			"email":      user.Email,
-			"role":       user.Role,
-			"is_active":  user.IsActive,
-			"created_at": user.CreatedAt,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"role":           user.Role,
+			"is_active":      user.IsActive,
+			"created_at":     user.CreatedAt,
 		},
 	}))
 }
@@ -114,15 +130,32 @@ func UpdateUser(c *gin.Context) {
 			return
 		}
 		updateData["email"] = req.Email
+		if req.Email != user.Email {
+			// A new address has to be verified again
+			updateData["email_verified"] = false
+			updateData["email_verified_at"] = nil
+		}
 	}
 
 	if req.Password != "" {
+		candidate := user
+		if req.Email != "" {
+			candidate.Email = req.Email
+		}
+		if req.Name != "" {
+			candidate.Name = req.Name
+		}
+		if rejectWeakPassword(c, "password", req.Password, candidate) {
+			return
+		}
+
 		hashedPassword, err := utils.HashPassword(req.Password)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to hash password"))
 			return
 		}
 		updateData["password"] = hashedPassword
+		updateData["password_changed_at"] = time.Now()
 	}
 
 	if req.RoleID != 0 {
@@ -139,27 +172,71 @@ func UpdateUser(c *gin.Context) {
 		updateData["is_active"] = *req.IsActive
 	}
 
-	// Update user
-	if err := database.DB.Model(&user).Updates(updateData).Error; err != nil {
+	// Deactivation or a role change must not leave old sessions with stale access
+	logoutRequired := (req.IsActive != nil && !*req.IsActive && user.IsActive) ||
+		(req.RoleID != 0 && req.RoleID != user.RoleID)
+
+	// Update user, remembering the outgoing password for the reuse check
+	oldPassword := user.Password
+	var emailChanged bool
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if emailChanged, err = saveUserUpdates(tx, &user, updateData); err != nil {
+			return err
+		}
+		if req.Password != "" {
+			return security.RememberPassword(tx, user.ID, oldPassword)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update user"))
 		return
 	}
 
+	if emailChanged {
+		tokens.Revoke(tokens.EmailVerification, user.ID)
+		if err := sendVerificationEmail(user); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
+	sessionsRevoked := 0
+	if logoutRequired {
+		sessionsRevoked, err = sessions.RevokeAll(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("User updated but failed to end their sessions"))
+			return
+		}
+	}
+
 	// Reload user with role
 	database.DB.Preload("Role").First(&user, user.ID)
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("User updated successfully", gin.H{
 		"user": gin.H{
-			"id":         user.ID,
-			"name":       user.Name,
-			"email":      user.Email,
-			"role":       user.Role,
-			"is_active":  user.IsActive,
-			"updated_at": user.UpdatedAt,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"role":           user.Role,
+			"is_active":      user.IsActive,
+			"updated_at":     user.UpdatedAt,
 		},
+		"sessions_revoked": sessionsRevoked,
 	}))
 }
 
+// saveUserUpdates applies updateData to the user and reports whether the email address
+// changed. Updates copies the values into user, so the old address is taken first.
+func saveUserUpdates(tx *gorm.DB, user *models.User, updateData map[string]interface{}) (bool, error) {
+	oldEmail := user.Email
+	if err := tx.Model(user).Updates(updateData).Error; err != nil {
+		return false, err
+	}
+	return user.Email != oldEmail, nil
+}
+
 // DeleteUser - Delete user (soft delete)
 func DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
@@ -194,18 +271,7 @@ func DeleteUser(c *gin.Context) {
 	}
 
 	// Invalidate all sessions for this user
-	keys, err := redis.Client.Keys(redis.Ctx, "session:*").Result()
-	if err == nil {
-		for _, key := range keys {
-			sessionData, err := redis.Client.Get(redis.Ctx, key).Result()
-			if err == nil {
-				var session models.Session
-				if json.Unmarshal([]byte(sessionData), &session) == nil && session.UserID == uint(id) {
-					redis.Client.Del(redis.Ctx, key)
-				}
-			}
-		}
-	}
+	sessions.RevokeAll(user.ID)
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("User deleted successfully", nil))
 }
@@ -233,17 +299,56 @@ func GetUserDetails(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("User details retrieved successfully", gin.H{
 		"user": gin.H{
-			"id":         user.ID,
-			"name":       user.Name,
-			"email":      user.Email,
-			"role":       user.Role,
-			"is_active":  user.IsActive,
-			"created_at": user.CreatedAt,
-			"updated_at": user.UpdatedAt,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"role":           user.Role,
+			"is_active":      user.IsActive,
+			"created_at":     user.CreatedAt,
+			"updated_at":     user.UpdatedAt,
+
+			"preferred_language": user.PreferredLanguage,
+			"date_of_birth":      user.DateOfBirth,
+			"age_verified":       user.AgeVerifiedAt != nil,
 		},
+		"impersonating": c.GetUint("impersonator_id") != 0,
+		"impersonation": impersonationDetails(c),
 	}))
 }
 
+// UpdatePreferences - Update the current user's preferences
+func UpdatePreferences(c *gin.Context) {
+	var req dtos.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	updateData := map[string]interface{}{}
+	if req.PreferredLanguage != nil {
+		code := i18n.Normalize(*req.PreferredLanguage)
+		if code != "" {
+			var count int64
+			database.DB.Model(&models.Language{}).Where("LOWER(code) = ? AND is_active = ?", code, true).Count(&count)
+			if count == 0 {
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse("Unsupported language"))
+				return
+			}
+		}
+		updateData["preferred_language"] = code
+	}
+
+	if len(updateData) > 0 {
+		if err := database.DB.Model(&models.User{}).Where("id = ?", c.GetUint("user_id")).Updates(updateData).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update preferences"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Preferences updated successfully", updateData))
+}
+
 // GetAllUsers - Get all users (admin only)
 func GetAllUsers(c *gin.Context) {
 	var users []models.User
@@ -286,13 +391,14 @@ func GetAllUsers(c *gin.Context) {
 	var userList []gin.H
 	for _, user := range users {
 		userList = append(userList, gin.H{
-			"id":         user.ID,
-			"name":       user.Name,
-			"email":      user.Email,
-			"role":       user.Role,
-			"is_active":  user.IsActive,
-			"created_at": user.CreatedAt,
-			"updated_at": user.UpdatedAt,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"role":           user.Role,
+			"is_active":      user.IsActive,
+			"created_at":     user.CreatedAt,
+			"updated_at":     user.UpdatedAt,
 		})
 	}
 
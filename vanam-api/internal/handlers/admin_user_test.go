@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// noConn is a connection pool for dry run sessions, which build statements without
+// sending them
+type noConn struct{}
+
+var errNoConn = errors.New("dry run: no database connection")
+
+func (noConn) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoConn
+}
+
+func (noConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errNoConn
+}
+
+func (noConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errNoConn
+}
+
+func (noConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: noConn{}}), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("failed to open dry run session: %v", err)
+	}
+	return db
+}
+
+func TestSaveUserUpdates(t *testing.T) {
+	tests := []struct {
+		name       string
+		updateData map[string]interface{}
+		want       bool
+	}{
+		{
+			name:       "new address",
+			updateData: map[string]interface{}{"email": "new@vanam.com", "email_verified": false, "email_verified_at": nil},
+			want:       true,
+		},
+		{
+			name:       "same address",
+			updateData: map[string]interface{}{"email": "old@vanam.com", "name": "Renamed"},
+		},
+		{
+			name:       "no address",
+			updateData: map[string]interface{}{"name": "Renamed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := models.User{ID: 7, Name: "Old", Email: "old@vanam.com", EmailVerified: true}
+			changed, err := saveUserUpdates(dryRunDB(t), &user, tt.updateData)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed != tt.want {
+				t.Errorf("changed = %v, want %v", changed, tt.want)
+			}
+			// The caller mails the verification link to the address now on the user
+			if email, ok := tt.updateData["email"]; ok && user.Email != email {
+				t.Errorf("user.Email = %q, want %q", user.Email, email)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// screeningCertification finds the certification that applies to a show: the movie's
+// certification in the theater's country, or in the default country the movie's legacy
+// rating. It returns nil when the movie isn't certified there.
+func screeningCertification(db *gorm.DB, screening models.Screening, movie models.Movie) (*models.Certification, error) {
+	var theater models.Theater
+	if err := db.Joins("JOIN screens ON screens.theater_id = theaters.id").
+		Where("screens.id = ?", screening.ScreenID).First(&theater).Error; err != nil {
+		return nil, err
+	}
+
+	country := strings.ToUpper(theater.Country)
+	if country == "" {
+		country = strings.ToUpper(config.App.DefaultCountry)
+	}
+
+	var certifications []models.Certification
+	if err := db.Joins("JOIN movie_certifications ON movie_certifications.certification_id = certifications.id").
+		Joins("JOIN certification_systems ON certification_systems.id = certifications.system_id").
+		Where("movie_certifications.movie_id = ? AND certification_systems.country = ?", movie.ID, country).
+		Limit(1).Find(&certifications).Error; err != nil {
+		return nil, err
+	}
+	if len(certifications) > 0 {
+		return &certifications[0], nil
+	}
+
+	if country != strings.ToUpper(config.App.DefaultCountry) || movie.Rating == "" {
+		return nil, nil
+	}
+	if err := db.Joins("JOIN certification_systems ON certification_systems.id = certifications.system_id").
+		Where("certification_systems.country = ? AND certifications.code = ?", country, movie.Rating).
+		Limit(1).Find(&certifications).Error; err != nil {
+		return nil, err
+	}
+	if len(certifications) > 0 {
+		return &certifications[0], nil
+	}
+	return nil, nil
+}
+
+// checkAgeGate refuses shows with a restricted certification to customers whose age
+// isn't known, or who will be too young on the day of the show
+func checkAgeGate(tx *gorm.DB, userID uint, screening models.Screening, movie models.Movie) error {
+	certification, err := screeningCertification(tx, screening, movie)
+	if err != nil {
+		return err
+	}
+	if certification == nil || !certification.Restricted {
+		return nil
+	}
+
+	rated := fmt.Sprintf("This show is rated %s (%d+)", certification.Code, certification.MinAge)
+
+	var user models.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		return &bookingError{http.StatusForbidden, rated + ", sign in with a customer account to book"}
+	}
+	if user.DateOfBirth == nil {
+		return &bookingError{http.StatusForbidden, rated + ", add your date of birth to your account to book"}
+	}
+	if config.App.AgeGateRequireVerified && user.AgeVerifiedAt == nil {
+		return &bookingError{http.StatusForbidden, rated + ", your age must be verified by the theater before you can book"}
+	}
+	if models.Age(*user.DateOfBirth, screening.StartsAt()) < certification.MinAge {
+		return &bookingError{http.StatusForbidden, fmt.Sprintf("%s and restricted to ages %d and over", rated, certification.MinAge)}
+	}
+	return nil
+}
+
+// UpdateDateOfBirth - Declare the current user's date of birth, needed for restricted shows
+func UpdateDateOfBirth(c *gin.Context) {
+	var req dtos.DateOfBirthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	dateOfBirth, msg := parseDateOfBirth(req.DateOfBirth)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, c.GetUint("user_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("User not found"))
+		return
+	}
+	if user.AgeVerifiedAt != nil {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Your age has been verified, ask the theater to correct your date of birth"))
+		return
+	}
+
+	if err := database.DB.Model(&user).Update("date_of_birth", dateOfBirth).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update date of birth"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Date of birth updated successfully", gin.H{
+		"date_of_birth": dateOfBirth.Format("2006-01-02"),
+		"age_verified":  false,
+	}))
+}
+
+// VerifyUserAge - Record a customer's date of birth as checked against ID (admin only)
+func VerifyUserAge(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.DateOfBirthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	dateOfBirth, msg := parseDateOfBirth(req.DateOfBirth)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+
+	now := time.Now()
+	verifiedBy := c.GetUint("user_id")
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"date_of_birth":   dateOfBirth,
+		"age_verified_at": now,
+		"age_verified_by": verifiedBy,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to verify age"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventAgeVerified, &user.ID, user.Email, "")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Age verified successfully", gin.H{
+		"user_id":         user.ID,
+		"date_of_birth":   dateOfBirth.Format("2006-01-02"),
+		"age_verified_at": now,
+	}))
+}
+
+// ClearAgeVerification - Withdraw a customer's age verification so they can correct their date of birth (admin only)
+func ClearAgeVerification(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"age_verified_at": nil,
+		"age_verified_by": nil,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to clear age verification"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Age verification cleared successfully", nil))
+}
+
+// parseDateOfBirth reads a YYYY-MM-DD date of birth, returning a message if it isn't plausible
+func parseDateOfBirth(s string) (time.Time, string) {
+	dateOfBirth, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	if err != nil {
+		return dateOfBirth, "date_of_birth must be a date like 1990-04-21"
+	}
+	now := time.Now()
+	if dateOfBirth.After(now) || models.Age(dateOfBirth, now) > 120 {
+		return dateOfBirth, "date_of_birth is not a plausible date of birth"
+	}
+	return dateOfBirth, ""
+}
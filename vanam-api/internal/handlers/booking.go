@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/seating"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// How long after the show ends a ticket code is still accepted at the door
+const ticketCodeGracePeriod = 30 * time.Minute
+
+// bookingError carries the HTTP status a booking failure should be reported with
+type bookingError struct {
+	Status  int
+	Message string
+}
+
+func (e *bookingError) Error() string {
+	return e.Message
+}
+
+// CreateBooking - Book seats for a screening and issue signed tickets
+func CreateBooking(c *gin.Context) {
+	var req dtos.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	if req.WithCompanion && !req.Accessible {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Companion seats are only available with accessible bookings"))
+		return
+	}
+
+	var booking *models.Booking
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		booking, err = bookSeats(tx, seatRequest{
+			UserID:        c.GetUint("user_id"),
+			ScreeningID:   req.ScreeningID,
+			SeatIDs:       req.SeatIDs,
+			Accessible:    req.Accessible,
+			WithCompanion: req.WithCompanion,
+		})
+		return err
+	})
+	if err != nil {
+		if bookingErr, ok := err.(*bookingError); ok {
+			c.JSON(bookingErr.Status, utils.ErrorResponse(bookingErr.Message))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create booking"))
+		return
+	}
+
+	// Reload booking with tickets
+	database.DB.Preload("Tickets").Preload("Screening.Movie").Preload("Screening.Screen.Theater").First(booking, booking.ID)
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Booking confirmed successfully", booking))
+}
+
+type seatRequest struct {
+	UserID        uint
+	ScreeningID   uint
+	SeatIDs       []uint
+	Accessible    bool // Customer asked for accessible seating
+	WithCompanion bool // Pair every accessible seat with an adjacent companion seat
+}
+
+// bookSeats reserves seats for a user inside tx and issues signed tickets for them
+func bookSeats(tx *gorm.DB, req seatRequest) (*models.Booking, error) {
+	// Reject duplicate seat IDs in the same request
+	seen := make(map[uint]bool)
+	for _, seatID := range req.SeatIDs {
+		if seen[seatID] {
+			return nil, &bookingError{http.StatusBadRequest, "Duplicate seat IDs in request"}
+		}
+		seen[seatID] = true
+	}
+
+	// Lock the screening so concurrent bookings serialize on its seat count
+	var screening models.Screening
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&screening, req.ScreeningID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &bookingError{http.StatusNotFound, "Screening not found"}
+		}
+		return nil, err
+	}
+
+	if !screening.IsActive || screening.Status == models.ScreeningCancelled || time.Now().After(screening.StartsAt()) {
+		return nil, &bookingError{http.StatusBadRequest, "Screening is not open for booking"}
+	}
+
+	var movie models.Movie
+	if err := tx.First(&movie, screening.MovieID).Error; err != nil {
+		return nil, err
+	}
+	if !movie.BookingOpen(time.Now()) {
+		if movie.Status == models.MovieEnded {
+			return nil, &bookingError{http.StatusBadRequest, "This movie is no longer showing"}
+		}
+		return nil, &bookingError{http.StatusBadRequest, "Bookings for this movie are not open yet"}
+	}
+	if err := checkAgeGate(tx, req.UserID, screening, movie); err != nil {
+		return nil, err
+	}
+
+	seatIDs := append([]uint(nil), req.SeatIDs...)
+	companions := make(map[uint]bool)
+	if req.WithCompanion {
+		companionIDs, err := pickCompanionSeats(tx, screening, req.SeatIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range companionIDs {
+			companions[id] = true
+		}
+		seatIDs = append(seatIDs, companionIDs...)
+	}
+
+	if screening.AvailableSeats < len(seatIDs) {
+		return nil, &bookingError{http.StatusConflict, "Not enough seats available"}
+	}
+
+	// Seats must belong to the screen the show is playing on
+	var seats []models.Seat
+	if err := tx.Where("id IN ? AND screen_id = ?", seatIDs, screening.ScreenID).Find(&seats).Error; err != nil {
+		return nil, err
+	}
+	if len(seats) != len(seatIDs) {
+		return nil, &bookingError{http.StatusBadRequest, "Some seat IDs are invalid for this screening"}
+	}
+
+	accessibleReleaseAt := accessibleSeatReleaseTime(screening)
+	for _, seat := range seats {
+		if seat.Status == "blocked" {
+			return nil, &bookingError{http.StatusConflict, "Seat " + seat.SeatNumber + " is not available"}
+		}
+		// Accessible seats are held for customers who need them until shortly before the show
+		if seat.IsAccessible && !req.Accessible && !companions[seat.ID] && time.Now().Before(accessibleReleaseAt) {
+			return nil, &bookingError{http.StatusConflict, "Seat " + seat.SeatNumber + " is reserved for accessible seating until " + accessibleReleaseAt.Format("15:04")}
+		}
+	}
+
+	// Check none of the seats are already taken for this screening
+	var takenCount int64
+	if err := tx.Model(&models.Ticket{}).
+		Where("screening_id = ? AND seat_id IN ? AND status <> ?", screening.ID, seatIDs, models.TicketCancelled).
+		Count(&takenCount).Error; err != nil {
+		return nil, err
+	}
+	if takenCount > 0 {
+		return nil, &bookingError{http.StatusConflict, "Some seats are already booked"}
+	}
+
+	booking := models.Booking{
+		BookingRef:   utils.GenerateBookingRef(),
+		UserID:       req.UserID,
+		ScreeningID:  screening.ID,
+		Status:       models.BookingConfirmed,
+		SeatCount:    len(seats),
+		IsAccessible: req.Accessible,
+	}
+
+	var tickets []models.Ticket
+	for _, seat := range seats {
+		price := seatPrice(screening, seat)
+		booking.TotalAmount += price
+		tickets = append(tickets, models.Ticket{
+			ScreeningID: screening.ID,
+			SeatID:      seat.ID,
+			SeatNumber:  seat.SeatNumber,
+			Price:       price,
+			Status:      models.TicketIssued,
+			IsCompanion: companions[seat.ID],
+		})
+	}
+
+	if err := tx.Create(&booking).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range tickets {
+		tickets[i].BookingID = booking.ID
+	}
+	if err := tx.Create(&tickets).Error; err != nil {
+		return nil, &bookingError{http.StatusConflict, "Some seats are already booked"}
+	}
+
+	// Sign ticket codes now that ticket IDs are known
+	for _, ticket := range tickets {
+		code, err := signTicket(ticket, screening)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&ticket).Update("code", code).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Model(&screening).Update("available_seats", gorm.Expr("available_seats - ?", len(seats))).Error; err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+// GetMyBookings - Get bookings for the logged-in user
+func GetMyBookings(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	// Pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := database.DB.Model(&models.Booking{}).Where("user_id = ?", userID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var bookings []models.Booking
+	if err := query.Preload("Tickets").Preload("Screening.Movie").Preload("Screening.Screen.Theater").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch bookings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginationResponse("Bookings retrieved successfully", bookings, page, limit, total))
+}
+
+// GetBookingByID - Get a booking owned by the logged-in user
+func GetBookingByID(c *gin.Context) {
+	bookingID := c.Param("id")
+	id, err := strconv.ParseUint(bookingID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		return
+	}
+
+	var booking models.Booking
+	if err := database.DB.Preload("Tickets.Seat").Preload("Screening.Movie").Preload("Screening.Screen.Theater").
+		Where("id = ? AND user_id = ?", id, c.GetUint("user_id")).First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Booking retrieved successfully", booking))
+}
+
+// pickCompanionSeats finds a free seat beside every accessible seat in the request
+func pickCompanionSeats(tx *gorm.DB, screening models.Screening, seatIDs []uint) ([]uint, error) {
+	var screen models.Screen
+	if err := tx.Preload("Seats").First(&screen, screening.ScreenID).Error; err != nil {
+		return nil, err
+	}
+
+	var layout models.SeatLayoutConfig
+	if err := json.Unmarshal([]byte(screen.SeatLayout), &layout); err != nil {
+		return nil, err
+	}
+
+	taken, err := takenSeatIDs(tx, screening.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := seating.BuildGrid(layout, screen.Seats, taken)
+
+	exclude := make(map[uint]bool)
+	for _, id := range seatIDs {
+		exclude[id] = true
+	}
+
+	var companionIDs []uint
+	for _, id := range seatIDs {
+		r, c, ok := grid.Find(id)
+		if !ok || !grid.Rows[r][c].Accessible {
+			continue
+		}
+
+		companion, ok := seating.CompanionFor(grid, id, exclude)
+		if !ok {
+			return nil, &bookingError{http.StatusConflict, "No free companion seat next to " + grid.Rows[r][c].Number}
+		}
+		exclude[companion.SeatID] = true
+		companionIDs = append(companionIDs, companion.SeatID)
+	}
+
+	if len(companionIDs) == 0 {
+		return nil, &bookingError{http.StatusBadRequest, "Companion seats can only be added to accessible seats"}
+	}
+
+	return companionIDs, nil
+}
+
+// accessibleSeatReleaseTime is when accessible seats open up to every customer
+func accessibleSeatReleaseTime(screening models.Screening) time.Time {
+	return screening.StartsAt().Add(-time.Duration(config.App.AccessibleSeatReleaseMinutes) * time.Minute)
+}
+
+// seatPrice resolves the ticket price for a seat at a screening
+func seatPrice(screening models.Screening, seat models.Seat) float64 {
+	if seat.Price > 0 {
+		return seat.Price
+	}
+	if seat.SeatType == "premium" && screening.PremiumPrice != nil {
+		return *screening.PremiumPrice
+	}
+	return screening.BasePrice
+}
+
+// signTicket builds the signed code for a ticket, valid until shortly after the show ends
+func signTicket(ticket models.Ticket, screening models.Screening) (string, error) {
+	return utils.SignTicketCode(utils.TicketClaims{
+		TicketID:    ticket.ID,
+		BookingID:   ticket.BookingID,
+		ScreeningID: ticket.ScreeningID,
+		SeatID:      ticket.SeatID,
+		SeatNumber:  ticket.SeatNumber,
+		ExpiresAt:   screening.EndsAt().Add(ticketCodeGracePeriod).Unix(),
+	})
+}
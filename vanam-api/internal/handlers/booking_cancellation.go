@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/payments"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errBookingNotActive = errors.New("booking is not active")
+
+type cancellationQuote struct {
+	Allowed         bool      `json:"allowed"`
+	Reason          string    `json:"reason,omitempty"`
+	PolicyID        *uint     `json:"policy_id"`
+	PolicyName      string    `json:"policy_name,omitempty"`
+	ShowStartsAt    time.Time `json:"show_starts_at"`
+	HoursBeforeShow float64   `json:"hours_before_show"`
+	RefundPercent   float64   `json:"refund_percent"`
+	RefundAmount    float64   `json:"refund_amount"`
+}
+
+type cancelOptions struct {
+	CancelledBy     uint
+	Forced          bool
+	Reason          string
+	PolicyID        *uint
+	HoursBeforeShow float64
+	RefundPercent   float64
+}
+
+// resolveCancellationPolicy picks the screening policy, then the theater policy, then the default
+func resolveCancellationPolicy(screening models.Screening) (*models.CancellationPolicy, error) {
+	var policy models.CancellationPolicy
+
+	err := database.DB.Preload("Tiers").Where("screening_id = ? AND is_active = ?", screening.ID, true).First(&policy).Error
+	if err == nil {
+		return &policy, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	var screen models.Screen
+	if err := database.DB.First(&screen, screening.ScreenID).Error; err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Preload("Tiers").Where("theater_id = ? AND is_active = ?", screen.TheaterID, true).First(&policy).Error
+	if err == nil {
+		return &policy, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	err = database.DB.Preload("Tiers").Where("is_default = ? AND is_active = ?", true, true).First(&policy).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// quoteCancellation applies a policy's sliding scale to a booking at the given moment
+func quoteCancellation(booking models.Booking, screening models.Screening, policy *models.CancellationPolicy, now time.Time) cancellationQuote {
+	startsAt := screening.StartsAt()
+	quote := cancellationQuote{
+		ShowStartsAt:    startsAt,
+		HoursBeforeShow: math.Round(startsAt.Sub(now).Hours()*100) / 100,
+	}
+
+	if booking.Status == models.BookingRebookOffered {
+		quote.Reason = "Screening was cancelled, rebook or decline the offer instead"
+		return quote
+	}
+	if booking.Status != models.BookingConfirmed {
+		quote.Reason = "Booking is already cancelled"
+		return quote
+	}
+
+	for _, ticket := range booking.Tickets {
+		if ticket.Status == models.TicketAdmitted {
+			quote.Reason = "Tickets have already been used"
+			return quote
+		}
+	}
+
+	if policy == nil {
+		quote.Reason = "Cancellation is not available for this screening"
+		return quote
+	}
+
+	quote.PolicyID = &policy.ID
+	quote.PolicyName = policy.Name
+
+	minutesLeft := startsAt.Sub(now).Minutes()
+	if minutesLeft < float64(policy.CutoffMinutes) {
+		quote.Reason = "Cancellation window has closed"
+		return quote
+	}
+
+	// Tiers are checked from the furthest out; the first one we are still ahead of applies
+	tiers := append([]models.CancellationTier(nil), policy.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].HoursBefore > tiers[j].HoursBefore })
+
+	for _, tier := range tiers {
+		if minutesLeft >= float64(tier.HoursBefore*60) {
+			quote.Allowed = true
+			quote.RefundPercent = tier.RefundPercent
+			quote.RefundAmount = refundAmount(booking.TotalAmount, tier.RefundPercent)
+			return quote
+		}
+	}
+
+	quote.Reason = "Cancellation window has closed"
+	return quote
+}
+
+func refundAmount(total, percent float64) float64 {
+	return math.Round(total*percent) / 100
+}
+
+// cancelBooking releases the booking's seats, records the cancellation and issues the refund
+func cancelBooking(bookingID uint, opts cancelOptions) (*models.BookingCancellation, *models.Refund, error) {
+	var booking models.Booking
+	var cancellation models.BookingCancellation
+	var refund *models.Refund
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, bookingID).Error; err != nil {
+			return err
+		}
+		if booking.Status != models.BookingConfirmed && booking.Status != models.BookingRebookOffered {
+			return errBookingNotActive
+		}
+
+		// Release seats back into the screening's inventory
+		released := tx.Model(&models.Ticket{}).
+			Where("booking_id = ? AND status <> ?", booking.ID, models.TicketCancelled).
+			Update("status", models.TicketCancelled)
+		if released.Error != nil {
+			return released.Error
+		}
+
+		if err := tx.Model(&models.Screening{}).Where("id = ?", booking.ScreeningID).
+			Update("available_seats", gorm.Expr("available_seats + ?", released.RowsAffected)).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&booking).Updates(map[string]interface{}{
+			"status":       models.BookingCancelled,
+			"cancelled_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		cancellation = models.BookingCancellation{
+			BookingID:       booking.ID,
+			PolicyID:        opts.PolicyID,
+			CancelledBy:     opts.CancelledBy,
+			Forced:          opts.Forced,
+			Reason:          opts.Reason,
+			HoursBeforeShow: opts.HoursBeforeShow,
+			RefundPercent:   opts.RefundPercent,
+			RefundAmount:    refundAmount(booking.TotalAmount, opts.RefundPercent),
+			SeatsReleased:   int(released.RowsAffected),
+		}
+		if err := tx.Create(&cancellation).Error; err != nil {
+			return err
+		}
+
+		if cancellation.RefundAmount > 0 {
+			refund = &models.Refund{
+				BookingID: booking.ID,
+				Amount:    cancellation.RefundAmount,
+				Provider:  payments.Default.Name(),
+				Status:    string(payments.RefundPending),
+			}
+			if err := tx.Create(refund).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if refund != nil {
+		issueRefund(refund, booking, opts.Reason)
+	}
+
+	return &cancellation, refund, nil
+}
+
+// issueRefund sends a recorded refund to the payment provider and stores the outcome
+func issueRefund(refund *models.Refund, booking models.Booking, reason string) {
+	result, err := payments.Default.Refund(context.Background(), payments.RefundRequest{
+		BookingID:  booking.ID,
+		BookingRef: booking.BookingRef,
+		Amount:     refund.Amount,
+		Reason:     reason,
+	})
+
+	if err != nil {
+		refund.Status = string(payments.RefundFailed)
+		refund.Error = err.Error()
+	} else {
+		refund.Status = string(result.Status)
+		refund.ProviderRef = result.ProviderRef
+	}
+
+	database.DB.Model(refund).Updates(map[string]interface{}{
+		"status":       refund.Status,
+		"provider_ref": refund.ProviderRef,
+		"error":        refund.Error,
+	})
+}
+
+// GetCancellationQuote - Show what the user would get back for cancelling now
+func GetCancellationQuote(c *gin.Context) {
+	booking, screening, ok := loadOwnBooking(c)
+	if !ok {
+		return
+	}
+
+	policy, err := resolveCancellationPolicy(screening)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to load cancellation policy"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Cancellation quote retrieved successfully",
+		quoteCancellation(booking, screening, policy, time.Now())))
+}
+
+// CancelBooking - Cancel the user's own booking under the applicable policy
+func CancelBooking(c *gin.Context) {
+	// The reason is optional, so an empty body is fine
+	var req dtos.CancelBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	booking, screening, ok := loadOwnBooking(c)
+	if !ok {
+		return
+	}
+
+	policy, err := resolveCancellationPolicy(screening)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to load cancellation policy"))
+		return
+	}
+
+	quote := quoteCancellation(booking, screening, policy, time.Now())
+	if !quote.Allowed {
+		c.JSON(http.StatusConflict, utils.ErrorResponse(quote.Reason))
+		return
+	}
+
+	cancellation, refund, err := cancelBooking(booking.ID, cancelOptions{
+		CancelledBy:     c.GetUint("user_id"),
+		Reason:          req.Reason,
+		PolicyID:        quote.PolicyID,
+		HoursBeforeShow: quote.HoursBeforeShow,
+		RefundPercent:   quote.RefundPercent,
+	})
+	if err == errBookingNotActive {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Booking is already cancelled"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to cancel booking"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Booking cancelled successfully", gin.H{
+		"cancellation": cancellation,
+		"refund":       refund,
+	}))
+}
+
+// AdminCancelBooking - Force-cancel any booking, ignoring the cancellation policy
+func AdminCancelBooking(c *gin.Context) {
+	bookingID := c.Param("id")
+	id, err := strconv.ParseUint(bookingID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		return
+	}
+
+	var req dtos.AdminCancelBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	var booking models.Booking
+	if err := database.DB.Preload("Screening").First(&booking, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return
+	}
+
+	percent := 100.0
+	if req.RefundPercent != nil {
+		percent = *req.RefundPercent
+	}
+
+	cancellation, refund, err := cancelBooking(booking.ID, cancelOptions{
+		CancelledBy:     c.GetUint("user_id"),
+		Forced:          true,
+		Reason:          req.Reason,
+		HoursBeforeShow: math.Round(time.Until(booking.Screening.StartsAt()).Hours()*100) / 100,
+		RefundPercent:   percent,
+	})
+	if err == errBookingNotActive {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Booking is already cancelled"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to cancel booking"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Booking force-cancelled successfully", gin.H{
+		"cancellation": cancellation,
+		"refund":       refund,
+	}))
+}
+
+// loadOwnBooking loads a booking owned by the logged-in user, writing the error response itself
+func loadOwnBooking(c *gin.Context) (models.Booking, models.Screening, bool) {
+	var booking models.Booking
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		return booking, models.Screening{}, false
+	}
+
+	if err := database.DB.Preload("Tickets").Preload("Screening").
+		Where("id = ? AND user_id = ?", id, c.GetUint("user_id")).First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
+			return booking, models.Screening{}, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return booking, models.Screening{}, false
+	}
+
+	return booking, booking.Screening, true
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/payments"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetRebookOptions - Show the open rebook offer and alternative shows for a cancelled booking
+func GetRebookOptions(c *gin.Context) {
+	booking, screening, ok := loadOwnBooking(c)
+	if !ok {
+		return
+	}
+
+	offer, ok := loadOpenRebookOffer(c, booking)
+	if !ok {
+		return
+	}
+
+	var alternatives []models.Screening
+	if err := database.DB.Preload("Screen.Theater").Preload("Language").
+		Where("movie_id = ? AND id <> ? AND is_active = ? AND status = ? AND show_date >= ? AND available_seats >= ?",
+			screening.MovieID, screening.ID, true, models.ScreeningScheduled, time.Now().Format("2006-01-02"), booking.SeatCount).
+		Order("show_date ASC, show_time ASC").Limit(20).
+		Find(&alternatives).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch alternative screenings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Rebook options retrieved successfully", gin.H{
+		"offer":        offer,
+		"booking":      booking,
+		"alternatives": alternatives,
+	}))
+}
+
+// AcceptRebookOffer - Move a booking from a cancelled show to another show of the same movie
+func AcceptRebookOffer(c *gin.Context) {
+	var req dtos.RebookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	booking, screening, ok := loadOwnBooking(c)
+	if !ok {
+		return
+	}
+
+	offer, ok := loadOpenRebookOffer(c, booking)
+	if !ok {
+		return
+	}
+
+	// Companion seats are picked again beside the new accessible seats, so only the
+	// customer's own seats are selected
+	companions := 0
+	for _, ticket := range booking.Tickets {
+		if ticket.IsCompanion {
+			companions++
+		}
+	}
+	if len(req.SeatIDs) != booking.SeatCount-companions {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(fmt.Sprintf("Select exactly %d seats", booking.SeatCount-companions)))
+		return
+	}
+
+	var target models.Screening
+	if err := database.DB.First(&target, req.ScreeningID).Error; err != nil || target.MovieID != screening.MovieID {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Rebooking is only possible to another show of the same movie"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var newBooking *models.Booking
+	var refund *models.Refund
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the offer so a double submit can't rebook twice
+		var locked models.RebookOffer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, offer.ID).Error; err != nil {
+			return err
+		}
+		if locked.Status != models.RebookOfferOpen {
+			return &bookingError{http.StatusConflict, "Rebook offer is no longer open"}
+		}
+
+		var err error
+		newBooking, err = bookSeats(tx, seatRequest{
+			UserID:        userID,
+			ScreeningID:   target.ID,
+			SeatIDs:       req.SeatIDs,
+			Accessible:    booking.IsAccessible,
+			WithCompanion: companions > 0,
+		})
+		if err != nil {
+			return err
+		}
+
+		if newBooking.TotalAmount > booking.TotalAmount {
+			return &bookingError{http.StatusBadRequest, fmt.Sprintf("Selected seats cost more than the original booking (%.2f)", booking.TotalAmount)}
+		}
+
+		// Original payment covers the new booking, refund whatever is left over
+		difference := booking.TotalAmount - newBooking.TotalAmount
+		cancellation := models.BookingCancellation{
+			BookingID:       booking.ID,
+			CancelledBy:     userID,
+			Forced:          true,
+			Reason:          fmt.Sprintf("Rebooked to %s", newBooking.BookingRef),
+			HoursBeforeShow: time.Until(screening.StartsAt()).Hours(),
+			RefundAmount:    difference,
+		}
+		if booking.TotalAmount > 0 {
+			cancellation.RefundPercent = difference / booking.TotalAmount * 100
+		}
+		if err := tx.Create(&cancellation).Error; err != nil {
+			return err
+		}
+
+		if difference > 0 {
+			refund = &models.Refund{
+				BookingID: booking.ID,
+				Amount:    difference,
+				Provider:  payments.Default.Name(),
+				Status:    string(payments.RefundPending),
+			}
+			if err := tx.Create(refund).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&booking).Updates(map[string]interface{}{
+			"status":       models.BookingCancelled,
+			"cancelled_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&locked).Updates(map[string]interface{}{
+			"status":         models.RebookOfferAccepted,
+			"new_booking_id": newBooking.ID,
+		}).Error
+	})
+	if err != nil {
+		if bookingErr, ok := err.(*bookingError); ok {
+			c.JSON(bookingErr.Status, utils.ErrorResponse(bookingErr.Message))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to rebook"))
+		return
+	}
+
+	if refund != nil {
+		issueRefund(refund, booking, "Price difference after rebooking")
+	}
+
+	database.DB.Preload("Tickets").Preload("Screening.Movie").Preload("Screening.Screen.Theater").First(newBooking, newBooking.ID)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Booking moved successfully", gin.H{
+		"booking": newBooking,
+		"refund":  refund,
+	}))
+}
+
+// DeclineRebookOffer - Take a full refund instead of moving to another show
+func DeclineRebookOffer(c *gin.Context) {
+	booking, screening, ok := loadOwnBooking(c)
+	if !ok {
+		return
+	}
+
+	offer, ok := loadOpenRebookOffer(c, booking)
+	if !ok {
+		return
+	}
+
+	cancellation, refund, err := closeRebookOffer(*offer, screening, c.GetUint("user_id"), models.RebookOfferDeclined)
+	if err == errBookingNotActive {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Booking is already cancelled"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to refund booking"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Booking refunded successfully", gin.H{
+		"cancellation": cancellation,
+		"refund":       refund,
+	}))
+}
+
+// ExpireRebookOffers refunds every rebook offer the customer didn't act on in time
+func ExpireRebookOffers() error {
+	var offers []models.RebookOffer
+	if err := database.DB.Where("status = ? AND expires_at < ?", models.RebookOfferOpen, time.Now()).
+		Find(&offers).Error; err != nil {
+		return err
+	}
+
+	// One offer failing must not hold up the refunds of the others
+	failed := 0
+	for _, offer := range offers {
+		var screening models.Screening
+		if err := database.DB.Preload("Movie").First(&screening, offer.ScreeningID).Error; err != nil {
+			log.Printf("❌ Failed to load screening %d of rebook offer %d: %v", offer.ScreeningID, offer.ID, err)
+			failed++
+			continue
+		}
+
+		cancellation, _, err := closeRebookOffer(offer, screening, 0, models.RebookOfferExpired)
+		if err != nil {
+			log.Printf("❌ Failed to refund expired rebook offer %d: %v", offer.ID, err)
+			failed++
+			continue
+		}
+
+		var booking models.Booking
+		if err := database.DB.First(&booking, offer.BookingID).Error; err == nil {
+			queueNotification(booking.UserID, "Your booking has been refunded",
+				fmt.Sprintf("Your rebook offer for booking %s expired, so it has been refunded in full (%.2f).",
+					booking.BookingRef, cancellation.RefundAmount))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d expired rebook offers could not be refunded", failed, len(offers))
+	}
+	return nil
+}
+
+// closeRebookOffer refunds the booking behind an offer in full and closes the offer
+func closeRebookOffer(offer models.RebookOffer, screening models.Screening, actorID uint, status models.RebookOfferStatus) (*models.BookingCancellation, *models.Refund, error) {
+	cancellation, refund, err := cancelBooking(offer.BookingID, cancelOptions{
+		CancelledBy:     actorID,
+		Forced:          true,
+		Reason:          "Screening cancelled: " + screening.CancellationReason,
+		HoursBeforeShow: time.Until(screening.StartsAt()).Hours(),
+		RefundPercent:   100,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	database.DB.Model(&offer).Update("status", status)
+	return cancellation, refund, nil
+}
+
+// loadOpenRebookOffer finds the booking's open, unexpired rebook offer, writing the error response itself
+func loadOpenRebookOffer(c *gin.Context, booking models.Booking) (*models.RebookOffer, bool) {
+	var offer models.RebookOffer
+	if err := database.DB.Where("booking_id = ?", booking.ID).First(&offer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("No rebook offer for this booking"))
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return nil, false
+	}
+
+	if offer.Status != models.RebookOfferOpen || time.Now().After(offer.ExpiresAt) {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Rebook offer is no longer open"))
+		return nil, false
+	}
+
+	return &offer, true
+}
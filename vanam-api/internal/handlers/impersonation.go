@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+)
+
+const defaultImpersonationDuration = 30 * time.Minute
+
+// ImpersonateUser - Start a time-boxed session acting as a customer, for support (admin only)
+func ImpersonateUser(c *gin.Context) {
+	var req dtos.ImpersonateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	agent := c.MustGet("user").(models.User)
+	if agent.Role.Name != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Admin access required"))
+		return
+	}
+
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+	database.DB.Preload("Role").First(user, user.ID)
+
+	switch {
+	case user.ID == agent.ID:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("You can't impersonate yourself"))
+		return
+	case !user.IsActive:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("User is inactive"))
+		return
+	case user.Role.Name == "admin":
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Admin accounts can't be impersonated"))
+		return
+	}
+
+	duration := defaultImpersonationDuration
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+
+	session := models.Session{
+		SessionID: utils.GenerateSecureToken(),
+		UserID:    user.ID,
+		RoleID:    user.RoleID,
+		CreatedAt: time.Now(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Device:    "Support session",
+
+		ImpersonatorID:      agent.ID,
+		ImpersonationReason: req.Reason,
+	}
+
+	if err := sessions.Create(&session, duration); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to start impersonation"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventImpersonationStarted, &user.ID, user.Email,
+		fmt.Sprintf("by %s for %s: %s", agent.Email, duration, req.Reason))
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Impersonation started", gin.H{
+		"token":      session.SessionID,
+		"expires_at": session.ExpiresAt,
+		"user": gin.H{
+			"id":    user.ID,
+			"name":  user.Name,
+			"email": user.Email,
+		},
+		"impersonator": gin.H{
+			"id":    agent.ID,
+			"name":  agent.Name,
+			"email": agent.Email,
+		},
+	}))
+}
+
+// impersonationDetails describes the current impersonation for /profile, or nil
+func impersonationDetails(c *gin.Context) gin.H {
+	agentID := c.GetUint("impersonator_id")
+	if agentID == 0 {
+		return nil
+	}
+
+	details := gin.H{"impersonator_id": agentID}
+
+	var agent models.User
+	if err := database.DB.Select("id", "name", "email").First(&agent, agentID).Error; err == nil {
+		details["impersonator_name"] = agent.Name
+		details["impersonator_email"] = agent.Email
+	}
+	if session, err := sessions.Get(c.GetString("session_id")); err == nil {
+		details["reason"] = session.ImpersonationReason
+		details["started_at"] = session.CreatedAt
+		details["expires_at"] = session.ExpiresAt
+	}
+
+	return details
+}
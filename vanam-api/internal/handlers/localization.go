@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/i18n"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+)
+
+// requestLanguages returns the fallback chain resolved by the localization middleware.
+// Routes without it still honour an explicit ?lang, and are otherwise not localized.
+func requestLanguages(c *gin.Context) []string {
+	if languages := c.GetStringSlice("languages"); len(languages) > 0 {
+		return languages
+	}
+	if lang := c.Query("lang"); lang != "" {
+		return i18n.Chain(lang)
+	}
+	return nil
+}
+
+// localizeMovies fills in each movie's Title and Description from the first language in
+// the request's chain that has a translation, and sets Content-Language to the languages
+// actually served. Movies without a matching translation are served as written, in their
+// original language, or the default language when that isn't recorded. Genres and
+// rating descriptions are localized too.
+func localizeMovies(c *gin.Context, movies []models.Movie) {
+	languages := requestLanguages(c)
+	if len(languages) == 0 || len(movies) == 0 {
+		return
+	}
+
+	movieIDs := make([]uint, len(movies))
+	var genreIDs, originalLanguageIDs []uint
+	for i, movie := range movies {
+		movieIDs[i] = movie.ID
+		if movie.OriginalLanguageID != nil {
+			originalLanguageIDs = append(originalLanguageIDs, *movie.OriginalLanguageID)
+		}
+		for _, genre := range movie.Genres {
+			genreIDs = append(genreIDs, genre.ID)
+		}
+	}
+
+	var translations []models.MovieLanguage
+	database.DB.Preload("Language").
+		Joins("JOIN languages ON languages.id = movie_languages.language_id").
+		Where("movie_languages.movie_id IN ? AND LOWER(languages.code) IN ?", movieIDs, languages).
+		Find(&translations)
+
+	byMovie := make(map[uint]map[string]models.MovieLanguage)
+	for _, translation := range translations {
+		if byMovie[translation.MovieID] == nil {
+			byMovie[translation.MovieID] = make(map[string]models.MovieLanguage)
+		}
+		byMovie[translation.MovieID][i18n.Normalize(translation.Language.Code)] = translation
+	}
+
+	originalLanguages := make(map[uint]string)
+	if len(originalLanguageIDs) > 0 {
+		var originals []models.Language
+		database.DB.Where("id IN ?", originalLanguageIDs).Find(&originals)
+		for _, language := range originals {
+			originalLanguages[language.ID] = i18n.Normalize(language.Code)
+		}
+	}
+
+	genreNames := genreTranslations(genreIDs, languages)
+	ratings := ratingTranslations(languages)
+	certifications := make(map[models.MovieRating]models.Certification)
+	if list, err := ratingCertifications(database.DB); err == nil {
+		for _, certification := range list {
+			certifications[models.MovieRating(certification.Code)] = certification
+		}
+	}
+
+	var served []string
+	seen := make(map[string]bool)
+	for i := range movies {
+		movie := &movies[i]
+		movie.Title = movie.OriginalTitle
+		movie.Language = firstAvailable(languages, func(code string) bool {
+			_, ok := byMovie[movie.ID][code]
+			return ok
+		})
+		if translation, ok := byMovie[movie.ID][movie.Language]; ok {
+			movie.Title = translation.Title
+			if translation.Description != "" {
+				movie.Description = translation.Description
+			}
+		} else if movie.OriginalLanguageID != nil {
+			if code, ok := originalLanguages[*movie.OriginalLanguageID]; ok {
+				movie.Language = code
+			}
+		}
+
+		for j := range movie.Genres {
+			applyGenreTranslation(&movie.Genres[j], genreNames, languages)
+		}
+
+		code := firstAvailable(languages, func(code string) bool {
+			_, ok := ratings[movie.Rating][code]
+			return ok
+		})
+		if rating, ok := ratings[movie.Rating][code]; ok {
+			movie.RatingLabel = rating.Label
+			movie.RatingDescription = rating.Description
+		} else if certification, ok := certifications[movie.Rating]; ok {
+			movie.RatingLabel = certification.Name
+			movie.RatingDescription = certification.Description
+		}
+
+		if !seen[movie.Language] {
+			seen[movie.Language] = true
+			served = append(served, movie.Language)
+		}
+	}
+
+	c.Header("Content-Language", strings.Join(served, ", "))
+}
+
+// localizeGenres sets each genre's Name from the first language in the request's chain
+// that has a translation
+func localizeGenres(c *gin.Context, genres []models.Genre) {
+	languages := requestLanguages(c)
+	if len(languages) == 0 || len(genres) == 0 {
+		return
+	}
+
+	genreIDs := make([]uint, len(genres))
+	for i, genre := range genres {
+		genreIDs[i] = genre.ID
+	}
+	names := genreTranslations(genreIDs, languages)
+
+	var served []string
+	seen := make(map[string]bool)
+	for i := range genres {
+		applyGenreTranslation(&genres[i], names, languages)
+		if !seen[genres[i].Language] {
+			seen[genres[i].Language] = true
+			served = append(served, genres[i].Language)
+		}
+	}
+
+	c.Header("Content-Language", strings.Join(served, ", "))
+}
+
+// genreTranslations loads genre names by genre ID and language code
+func genreTranslations(genreIDs []uint, languages []string) map[uint]map[string]string {
+	names := make(map[uint]map[string]string)
+	if len(genreIDs) == 0 {
+		return names
+	}
+
+	var translations []models.GenreLanguage
+	database.DB.Preload("Language").
+		Joins("JOIN languages ON languages.id = genre_languages.language_id").
+		Where("genre_languages.genre_id IN ? AND LOWER(languages.code) IN ?", genreIDs, languages).
+		Find(&translations)
+
+	for _, translation := range translations {
+		if names[translation.GenreID] == nil {
+			names[translation.GenreID] = make(map[string]string)
+		}
+		names[translation.GenreID][i18n.Normalize(translation.Language.Code)] = translation.Name
+	}
+	return names
+}
+
+func applyGenreTranslation(genre *models.Genre, names map[uint]map[string]string, languages []string) {
+	genre.Language = firstAvailable(languages, func(code string) bool {
+		_, ok := names[genre.ID][code]
+		return ok
+	})
+	if name, ok := names[genre.ID][genre.Language]; ok {
+		genre.Name = name
+	}
+}
+
+// ratingTranslations loads rating descriptions by rating and language code
+func ratingTranslations(languages []string) map[models.MovieRating]map[string]models.RatingDescription {
+	var descriptions []models.RatingDescription
+	database.DB.Preload("Language").
+		Joins("JOIN languages ON languages.id = rating_descriptions.language_id").
+		Where("LOWER(languages.code) IN ?", languages).
+		Find(&descriptions)
+
+	ratings := make(map[models.MovieRating]map[string]models.RatingDescription)
+	for _, description := range descriptions {
+		if ratings[description.Rating] == nil {
+			ratings[description.Rating] = make(map[string]models.RatingDescription)
+		}
+		ratings[description.Rating][i18n.Normalize(description.Language.Code)] = description
+	}
+	return ratings
+}
+
+// firstAvailable returns the first language in the chain that has content, or the
+// default language when none do, since untranslated content is written in it
+func firstAvailable(languages []string, has func(code string) bool) string {
+	for _, code := range languages {
+		if has(code) {
+			return code
+		}
+	}
+	return i18n.Normalize(config.App.DefaultLanguage)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// preloadCredits preloads every credit of a movie with its person, in billing order
+func preloadCredits(db *gorm.DB) *gorm.DB {
+	return db.Preload("MovieCasts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("billing_order ASC, person_id ASC")
+	}).Preload("MovieCasts.Person")
+}
+
+func arrangeCredits(movies []models.Movie) {
+	for i := range movies {
+		movies[i].ArrangeCredits()
+	}
+}
+
+// attachTrailers sets the movie's trailers, primary first
+func attachTrailers(movie *models.Movie) {
+	var trailers []models.MediaAsset
+	database.DB.Where("movie_id = ? AND kind = ?", movie.ID, models.MediaTrailer).
+		Order("is_primary DESC, sort_order ASC, id ASC").Find(&trailers)
+	movie.Trailers = trailers
+}
+
+// replaceActors makes the people given the movie's actors, billed in that order. Crew
+// credits are kept, as are the character names of actors who stay on.
+func replaceActors(tx *gorm.DB, movieID uint, personIDs []uint) error {
+	var current []models.MovieCast
+	if err := tx.Where("movie_id = ? AND role = ?", movieID, models.RoleActor).Find(&current).Error; err != nil {
+		return err
+	}
+	characters := make(map[uint]string)
+	for _, credit := range current {
+		characters[credit.PersonID] = credit.CharacterName
+	}
+
+	var credits []models.MovieCast
+	seen := make(map[uint]bool)
+	for _, personID := range personIDs {
+		if seen[personID] {
+			continue
+		}
+		seen[personID] = true
+		credits = append(credits, models.MovieCast{
+			MovieID:       movieID,
+			PersonID:      personID,
+			Role:          models.RoleActor,
+			CharacterName: characters[personID],
+			BillingOrder:  len(credits) + 1,
+		})
+	}
+
+	if err := tx.Where("movie_id = ? AND role = ?", movieID, models.RoleActor).Delete(&models.MovieCast{}).Error; err != nil {
+		return err
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Create(&credits).Error
+}
+
+// resolveCredits turns credit requests into rows for the movie, matching people given by
+// name case-insensitively and creating the ones that don't exist yet. A credit without a
+// billing order goes after the ones before it in the same role. The message is set when
+// a request is invalid.
+func resolveCredits(tx *gorm.DB, movieID uint, reqs []dtos.CreditRequest) ([]models.MovieCast, string, error) {
+	credits := make([]models.MovieCast, 0, len(reqs))
+	seen := make(map[string]bool)
+	billed := make(map[models.CreditRole]int)
+
+	for i, req := range reqs {
+		name := strings.TrimSpace(req.PersonName)
+		var person models.Person
+		switch {
+		case req.PersonID != 0:
+			if err := tx.First(&person, req.PersonID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return nil, fmt.Sprintf("credits[%d]: person %d not found", i, req.PersonID), nil
+				}
+				return nil, "", err
+			}
+		case name != "":
+			var matches []models.Person
+			if err := tx.Where("LOWER(name) = ?", strings.ToLower(name)).Order("id ASC").Limit(1).Find(&matches).Error; err != nil {
+				return nil, "", err
+			}
+			if len(matches) > 0 {
+				person = matches[0]
+			} else {
+				person = models.Person{Name: name}
+				if err := tx.Create(&person).Error; err != nil {
+					return nil, "", err
+				}
+			}
+		default:
+			return nil, fmt.Sprintf("credits[%d]: person_id or person_name is required", i), nil
+		}
+
+		role := models.CreditRole(req.Role)
+		key := fmt.Sprintf("%d:%s", person.ID, role)
+		if seen[key] {
+			return nil, fmt.Sprintf("credits[%d]: %s is already credited as %s", i, person.Name, role), nil
+		}
+		seen[key] = true
+
+		billingOrder := req.BillingOrder
+		if billingOrder == 0 {
+			billingOrder = billed[role] + 1
+		}
+		if billingOrder > billed[role] {
+			billed[role] = billingOrder
+		}
+
+		characterName := ""
+		if role == models.RoleActor {
+			characterName = strings.TrimSpace(req.CharacterName)
+		}
+
+		credits = append(credits, models.MovieCast{
+			MovieID:       movieID,
+			PersonID:      person.ID,
+			Role:          role,
+			CharacterName: characterName,
+			BillingOrder:  billingOrder,
+		})
+	}
+	return credits, "", nil
+}
+
+// replaceCredits replaces every credit of the movie, cast and crew
+func replaceCredits(tx *gorm.DB, movieID uint, credits []models.MovieCast) error {
+	if err := tx.Where("movie_id = ?", movieID).Delete(&models.MovieCast{}).Error; err != nil {
+		return err
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Create(&credits).Error
+}
+
+// GetMovieCredits - Get a movie's cast and crew in billing order
+func GetMovieCredits(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	if err := preloadCredits(database.DB).First(&movie, movie.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch credits"))
+		return
+	}
+	movie.ArrangeCredits()
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Credits retrieved successfully", movie.Credits))
+}
+
+// SetMovieCredits - Replace a movie's cast and crew (admin only)
+func SetMovieCredits(c *gin.Context) {
+	movie, ok := loadMovie(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.MovieCreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	var msg string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		credits, invalid, err := resolveCredits(tx, movie.ID, req.Credits)
+		if err != nil {
+			return err
+		}
+		if invalid != "" {
+			msg = invalid
+			return errors.New(invalid)
+		}
+		return replaceCredits(tx, movie.ID, credits)
+	})
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(msg))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update credits"))
+		return
+	}
+
+	preloadCredits(database.DB).First(&movie, movie.ID)
+	movie.ArrangeCredits()
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Credits updated successfully", movie.Credits))
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetNowShowingMovies - Movies currently in theaters
+func GetNowShowingMovies(c *gin.Context) {
+	listMoviesByStatus(c, "Now showing movies retrieved successfully", "release_date DESC", models.MovieNowShowing)
+}
+
+// GetComingSoonMovies - Upcoming movies, including ones already open for advance booking
+func GetComingSoonMovies(c *gin.Context) {
+	listMoviesByStatus(c, "Coming soon movies retrieved successfully", "release_date ASC", models.MovieComingSoon, models.MovieAdvanceBooking)
+}
+
+func listMoviesByStatus(c *gin.Context, message, order string, statuses ...models.MovieStatus) {
+	var movies []models.Movie
+
+	// Pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := database.DB.Model(&models.Movie{}).Where("status IN ? AND is_active = ?", statuses, true)
+
+	var total int64
+	query.Count(&total)
+
+	if err := query.Preload("Genres").Order(order).Offset(offset).Limit(limit).Find(&movies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch movies"))
+		return
+	}
+
+	localizeMovies(c, movies)
+	attachPrimaryImages(movies)
+
+	c.JSON(http.StatusOK, utils.PaginationResponse(message, movies, page, limit, total))
+}
+
+// UpdateMovieStatuses moves movies through their lifecycle as their dates pass.
+// Ended movies are picked up again by refreshMovieStatus when new screenings are added.
+func UpdateMovieStatuses() error {
+	var movies []models.Movie
+	if err := database.DB.Where("status <> ? AND status_locked = ?", models.MovieEnded, false).
+		Find(&movies).Error; err != nil {
+		return err
+	}
+
+	// One movie failing must not hold up the rest
+	failed := 0
+	for _, movie := range movies {
+		if err := syncMovieStatus(database.DB, &movie); err != nil {
+			log.Printf("❌ Failed to update status of movie %d: %v", movie.ID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d movie statuses could not be updated", failed, len(movies))
+	}
+	return nil
+}
+
+// refreshMovieStatus re-evaluates one movie, for use after its dates or screenings change
+func refreshMovieStatus(db *gorm.DB, movieID uint) error {
+	var movie models.Movie
+	if err := db.First(&movie, movieID).Error; err != nil {
+		return err
+	}
+	if movie.StatusLocked {
+		return nil
+	}
+	return syncMovieStatus(db, &movie)
+}
+
+// syncMovieStatus fills in missing lifecycle dates and stores the status they imply
+func syncMovieStatus(db *gorm.DB, movie *models.Movie) error {
+	updates := map[string]interface{}{}
+	if movie.ComingSoonAt == nil || movie.BookingOpensAt == nil {
+		comingSoonAt, bookingOpensAt := defaultLifecycleDates(movie.ReleaseDate)
+		if movie.ComingSoonAt == nil {
+			movie.ComingSoonAt = &comingSoonAt
+			updates["coming_soon_at"] = comingSoonAt
+		}
+		if movie.BookingOpensAt == nil {
+			movie.BookingOpensAt = &bookingOpensAt
+			updates["booking_opens_at"] = bookingOpensAt
+		}
+	}
+
+	lastShowEndsAt, err := lastShowEnd(db, movie.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status := movie.StatusAt(now, lastShowEndsAt)
+	if status != movie.Status {
+		updates["status"] = status
+		updates["status_changed_at"] = now
+		if status == models.MovieEnded {
+			updates["ended_at"] = *lastShowEndsAt
+		} else {
+			updates["ended_at"] = nil
+		}
+		log.Printf("🎬 Movie %d %q is now %s", movie.ID, movie.OriginalTitle, status)
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+	return db.Model(&models.Movie{}).Where("id = ? AND status_locked = ?", movie.ID, false).Updates(updates).Error
+}
+
+// defaultLifecycleDates derives the coming soon and advance booking dates from the release
+func defaultLifecycleDates(releaseDate time.Time) (comingSoonAt, bookingOpensAt time.Time) {
+	return releaseDate.AddDate(0, 0, -config.App.MovieComingSoonDays),
+		releaseDate.AddDate(0, 0, -config.App.MovieAdvanceBookingDays)
+}
+
+// validateLifecycleDates checks that a movie is announced, opened for booking and
+// released in that order
+func validateLifecycleDates(comingSoonAt, bookingOpensAt, releaseDate time.Time) string {
+	if bookingOpensAt.Before(comingSoonAt) {
+		return "booking_opens_at must not be before coming_soon_at"
+	}
+	if releaseDate.Before(bookingOpensAt) {
+		return "booking_opens_at must not be after the release date"
+	}
+	return ""
+}
+
+// lastShowEnd finds when the movie's last scheduled screening ends, or nil if it has none
+func lastShowEnd(db *gorm.DB, movieID uint) (*time.Time, error) {
+	var screenings []models.Screening
+	err := db.Where("movie_id = ? AND status <> ? AND show_date = (?)", movieID, models.ScreeningCancelled,
+		db.Model(&models.Screening{}).Select("MAX(show_date)").Where("movie_id = ? AND status <> ?", movieID, models.ScreeningCancelled),
+	).Find(&screenings).Error
+	if err != nil || len(screenings) == 0 {
+		return nil, err
+	}
+
+	last := screenings[0].EndsAt()
+	for _, screening := range screenings[1:] {
+		if ends := screening.EndsAt(); ends.After(last) {
+			last = ends
+		}
+	}
+	return &last, nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/mailer"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+)
+
+// queueNotification stores a message in the outbox for delivery to the user
+func queueNotification(userID uint, subject, body string) error {
+	notification := models.Notification{
+		UserID:  userID,
+		Channel: "email",
+		Subject: subject,
+		Body:    body,
+		Status:  "pending",
+	}
+
+	if err := database.DB.Create(&notification).Error; err != nil {
+		return err
+	}
+
+	log.Printf("📨 Notification queued for user %d: %s", userID, subject)
+	return nil
+}
+
+// Delay before the first retry of a failed notification, doubling with each attempt
+const notificationRetryDelay = time.Minute
+
+// DeliverNotifications sends pending outbox notifications through the mailer. A failed
+// send is retried with exponential backoff and marked failed after the last attempt.
+func DeliverNotifications() error {
+	now := time.Now()
+	var notifications []models.Notification
+	if err := database.DB.Where("status = ? AND channel = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", "pending", "email", now).
+		Order("created_at ASC").Limit(100).
+		Find(&notifications).Error; err != nil {
+		return err
+	}
+
+	for _, notification := range notifications {
+		var user models.User
+		if err := database.DB.First(&user, notification.UserID).Error; err != nil {
+			database.DB.Model(&notification).Updates(map[string]interface{}{
+				"status":     "failed",
+				"last_error": "user not found",
+			})
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		err := mailer.Default.Send(ctx, mailer.Message{To: user.Email, Subject: notification.Subject, Body: notification.Body})
+		cancel()
+
+		if err != nil {
+			attempts := notification.Attempts + 1
+			updateData := map[string]interface{}{
+				"attempts":   attempts,
+				"last_error": err.Error(),
+			}
+			if attempts >= config.App.NotificationMaxAttempts {
+				log.Printf("❌ Failed to deliver notification %d after %d attempts: %v", notification.ID, attempts, err)
+				updateData["status"] = "failed"
+			} else {
+				retryAt := time.Now().Add(notificationRetryDelay << (attempts - 1))
+				log.Printf("⚠️  Failed to deliver notification %d, retrying at %s: %v", notification.ID, retryAt.Format(time.RFC3339), err)
+				updateData["next_attempt_at"] = retryAt
+			}
+			database.DB.Model(&notification).Updates(updateData)
+			continue
+		}
+
+		database.DB.Model(&notification).Updates(map[string]interface{}{
+			"status":   "sent",
+			"sent_at":  time.Now(),
+			"attempts": notification.Attempts + 1,
+		})
+	}
+
+	return nil
+}
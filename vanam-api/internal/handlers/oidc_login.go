@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/oidc"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+	"gorm.io/gorm"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+var errSSORoleNotMapped = errors.New("no role is mapped to the user's groups")
+
+func oidcStateKey(state string) string {
+	return "oidc_state:" + state
+}
+
+// OIDCLogin - Start single sign-on and redirect to the identity provider
+func OIDCLogin(c *gin.Context) {
+	if oidc.Default == nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(oidc.ErrNotConfigured.Error()))
+		return
+	}
+
+	authReq, err := oidc.NewAuthRequest()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to start single sign-on"))
+		return
+	}
+
+	authURL, err := oidc.Default.AuthURL(c.Request.Context(), authReq)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		c.JSON(http.StatusBadGateway, utils.ErrorResponse("Identity provider is unavailable"))
+		return
+	}
+
+	data, _ := json.Marshal(authReq)
+	if err := redis.Client.Set(redis.Ctx, oidcStateKey(authReq.State), data, oidcStateTTL).Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to start single sign-on"))
+		return
+	}
+
+	// SPAs that can't follow a redirect from XHR ask for the URL instead
+	if c.Query("redirect") == "false" {
+		c.JSON(http.StatusOK, utils.SuccessResponse("Authorization URL created", gin.H{
+			"authorization_url": authURL,
+			"state":             authReq.State,
+		}))
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback - Finish single sign-on and issue a session
+func OIDCCallback(c *gin.Context) {
+	if oidc.Default == nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(oidc.ErrNotConfigured.Error()))
+		return
+	}
+
+	if idpErr := c.Query("error"); idpErr != "" {
+		recordSecurityEvent(c, models.EventSSOFailed, nil, "", "provider error: "+idpErr)
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Sign-in was cancelled or refused by the identity provider"))
+		return
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("state and code are required"))
+		return
+	}
+
+	// State is single use, which also stops a callback URL from being replayed
+	data, err := redis.Client.GetDel(redis.Ctx, oidcStateKey(state)).Result()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Sign-in expired, please try again"))
+		return
+	}
+
+	var authReq oidc.AuthRequest
+	if err := json.Unmarshal([]byte(data), &authReq); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Sign-in expired, please try again"))
+		return
+	}
+
+	claims, err := oidc.Default.Exchange(c.Request.Context(), code, &authReq)
+	if err != nil {
+		log.Printf("OIDC callback failed: %v", err)
+		recordSecurityEvent(c, models.EventSSOFailed, nil, "", err.Error())
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Could not verify sign-in with the identity provider"))
+		return
+	}
+
+	user, err := provisionSSOUser(c, claims)
+	if err != nil {
+		recordSecurityEvent(c, models.EventSSOFailed, nil, claims.String("email"), err.Error())
+		if err == errSSORoleNotMapped {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Your account is not allowed to access this application"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to sign in"))
+		return
+	}
+
+	if !user.IsActive {
+		recordSecurityEvent(c, models.EventSSOFailed, &user.ID, user.Email, "account inactive")
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Account is inactive"))
+		return
+	}
+
+	// Like AdminLogin, only admins get a session; the admin API doesn't check roles per route
+	if user.Role.Name != "admin" {
+		recordSecurityEvent(c, models.EventSSOFailed, &user.ID, user.Email, "role "+user.Role.Name+" has no admin access")
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Your account is not allowed to access this application"))
+		return
+	}
+
+	beginSession(c, *user)
+}
+
+// provisionSSOUser finds the local user for an IdP identity, creating it just in time,
+// and keeps their role in sync with their IdP groups
+func provisionSSOUser(c *gin.Context, claims oidc.Claims) (*models.User, error) {
+	provider := oidc.Default
+	subject := claims.String("sub")
+	email := strings.ToLower(claims.String("email"))
+
+	roleName := provider.RoleFor(claims.Strings(provider.GroupsClaim))
+	if roleName == "" {
+		return nil, errSSORoleNotMapped
+	}
+
+	var role models.Role
+	if err := database.DB.Where("name = ?", roleName).First(&role).Error; err != nil {
+		return nil, fmt.Errorf("mapped role %q does not exist", roleName)
+	}
+
+	var user models.User
+	err := database.DB.Where("oidc_issuer = ? AND oidc_subject = ?", provider.Issuer, subject).First(&user).Error
+	if err == gorm.ErrRecordNotFound && email != "" && claims.Bool("email_verified") {
+		// Link an existing password account, but only on an address the IdP has verified
+		err = database.DB.Where("LOWER(email) = ?", email).First(&user).Error
+		if err == nil {
+			if err := database.DB.Model(&user).Updates(map[string]interface{}{
+				"oidc_issuer":  provider.Issuer,
+				"oidc_subject": subject,
+			}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err == gorm.ErrRecordNotFound {
+		if email == "" {
+			return nil, errors.New("identity provider did not return an email address")
+		}
+		return createSSOUser(c, claims, email, role)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A role change must not leave old sessions with stale access, as in UpdateUser
+	if user.RoleID != role.ID {
+		if err := database.DB.Model(&user).Update("role_id", role.ID).Error; err != nil {
+			return nil, err
+		}
+		revoked, err := sessions.RevokeAll(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked > 0 {
+			recordSecurityEvent(c, models.EventSessionsRevoked, &user.ID, user.Email,
+				fmt.Sprintf("%d sessions revoked after SSO role change to %s", revoked, role.Name))
+		}
+	}
+
+	database.DB.Preload("Role").First(&user, user.ID)
+	return &user, nil
+}
+
+func createSSOUser(c *gin.Context, claims oidc.Claims, email string, role models.Role) (*models.User, error) {
+	// SSO users never log in with a password, so give them one nobody knows
+	hashedPassword, err := utils.HashPassword(utils.GenerateSecureToken())
+	if err != nil {
+		return nil, err
+	}
+
+	name := claims.String("name")
+	if name == "" {
+		name = email
+	}
+
+	user := models.User{
+		Email:         email,
+		Password:      hashedPassword,
+		Name:          name,
+		RoleID:        role.ID,
+		IsActive:      true,
+		EmailVerified: claims.Bool("email_verified"),
+		OIDCIssuer:    oidc.Default.Issuer,
+		OIDCSubject:   claims.String("sub"),
+	}
+	if user.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := database.DB.Create(&user).Error; err != nil {
+		return nil, err
+	}
+
+	recordSecurityEvent(c, models.EventSSOUserProvisioned, &user.ID, user.Email, "role "+role.Name)
+
+	database.DB.Preload("Role").First(&user, user.ID)
+	return &user, nil
+}
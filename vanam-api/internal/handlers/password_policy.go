@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+)
+
+// GetPasswordPolicy - Get the password rules so forms can show them up front
+func GetPasswordPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse("Password policy retrieved successfully", gin.H{
+		"policy": security.CurrentPasswordPolicy(),
+	}))
+}
+
+// rejectWeakPassword checks a password being set for user against the policy and their
+// password history. When it falls short it writes the violations and returns true.
+func rejectWeakPassword(c *gin.Context, field, password string, user models.User) bool {
+	violations := security.ValidatePassword(password, user.Email, user.Name)
+	if user.ID != 0 && security.PasswordReused(user, password) {
+		violations = append(violations, security.PasswordViolation{
+			Code:    "reused",
+			Message: fmt.Sprintf("Password must differ from the last %d passwords", security.CurrentPasswordPolicy().HistorySize),
+		})
+	}
+	if len(violations) == 0 {
+		return false
+	}
+
+	errors := make([]utils.FieldError, len(violations))
+	for i, violation := range violations {
+		errors[i] = utils.FieldError{Field: field, Code: violation.Code, Message: violation.Message}
+	}
+
+	c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse("Password does not meet the password policy", errors))
+	return true
+}
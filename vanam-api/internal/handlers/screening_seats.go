@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/seating"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const maxSuggestedSeats = 10
+
+// SuggestSeats - Recommend the best groups of adjacent free seats for a screening
+func SuggestSeats(c *gin.Context) {
+	count, err := strconv.Atoi(c.DefaultQuery("count", "2"))
+	if err != nil || count < 1 || count > maxSuggestedSeats {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("count must be between 1 and 10"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "3"))
+	if limit < 1 {
+		limit = 3
+	}
+
+	screening, grid, ok := loadScreeningGrid(c)
+	if !ok {
+		return
+	}
+
+	// Accessible seats are only suggested to customers who need them while they are held
+	accessible := c.Query("accessible") == "true"
+	suggestions := seating.Suggest(grid, seating.Options{
+		Count:             count,
+		SeatType:          c.Query("type"),
+		Limit:             limit,
+		ExcludeAccessible: !accessible && time.Now().Before(accessibleSeatReleaseTime(screening)),
+	})
+
+	// Price each suggestion the same way a booking would
+	var results []gin.H
+	for _, suggestion := range suggestions {
+		total := 0.0
+		for i, cell := range suggestion.Seats {
+			suggestion.Seats[i].Price = seatPrice(screening, models.Seat{Price: cell.Price, SeatType: cell.Type})
+			total += suggestion.Seats[i].Price
+		}
+		results = append(results, gin.H{
+			"seats":       suggestion.Seats,
+			"score":       suggestion.Score,
+			"orphans":     suggestion.Orphans,
+			"total_price": total,
+		})
+	}
+
+	if len(results) == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("No group of adjacent seats available"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Seat suggestions retrieved successfully", results))
+}
+
+// GetScreeningSeatMap - Get the seat map of a screening with availability and accessibility counts
+func GetScreeningSeatMap(c *gin.Context) {
+	screening, grid, ok := loadScreeningGrid(c)
+	if !ok {
+		return
+	}
+
+	onlyAccessible := c.Query("accessible") == "true"
+	onlyAvailable := c.Query("available") == "true"
+
+	total, available, accessibleTotal, accessibleAvailable := 0, 0, 0, 0
+	seats := []seating.Cell{}
+	for _, row := range grid.Rows {
+		for i, cell := range row {
+			if cell.Aisle {
+				continue
+			}
+			row[i].Price = seatPrice(screening, models.Seat{Price: cell.Price, SeatType: cell.Type})
+
+			total++
+			if !cell.Taken {
+				available++
+			}
+			if cell.Accessible {
+				accessibleTotal++
+				if !cell.Taken {
+					accessibleAvailable++
+				}
+			}
+
+			if (onlyAccessible && !cell.Accessible) || (onlyAvailable && cell.Taken) {
+				continue
+			}
+			seats = append(seats, row[i])
+		}
+	}
+
+	var reservedUntil *time.Time
+	if releaseAt := accessibleSeatReleaseTime(screening); time.Now().Before(releaseAt) {
+		reservedUntil = &releaseAt
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Seat map retrieved successfully", gin.H{
+		"screening_id": screening.ID,
+		"rows":         grid.Rows,
+		"seats":        seats,
+		"counts": gin.H{
+			"total":                total,
+			"available":            available,
+			"accessible_total":     accessibleTotal,
+			"accessible_available": accessibleAvailable,
+		},
+		"reserved_until": reservedUntil,
+	}))
+}
+
+// loadScreeningGrid loads a screening and its seat grid with current availability,
+// writing the error response itself
+func loadScreeningGrid(c *gin.Context) (models.Screening, seating.Grid, bool) {
+	var screening models.Screening
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid screening ID"))
+		return screening, seating.Grid{}, false
+	}
+
+	if err := database.DB.Preload("Screen.Seats").First(&screening, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Screening not found"))
+			return screening, seating.Grid{}, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return screening, seating.Grid{}, false
+	}
+
+	if !screening.IsActive || screening.Status == models.ScreeningCancelled {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Screening is not open for booking"))
+		return screening, seating.Grid{}, false
+	}
+
+	var layout models.SeatLayoutConfig
+	if err := json.Unmarshal([]byte(screening.Screen.SeatLayout), &layout); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Screen has an invalid seat layout"))
+		return screening, seating.Grid{}, false
+	}
+
+	taken, err := takenSeatIDs(database.DB, screening.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to check seat availability"))
+		return screening, seating.Grid{}, false
+	}
+
+	return screening, seating.BuildGrid(layout, screening.Screen.Seats, taken), true
+}
+
+// takenSeatIDs returns the seats that already have a live ticket for a screening
+func takenSeatIDs(tx *gorm.DB, screeningID uint) (map[uint]bool, error) {
+	var seatIDs []uint
+	if err := tx.Model(&models.Ticket{}).
+		Where("screening_id = ? AND status <> ?", screeningID, models.TicketCancelled).
+		Pluck("seat_id", &seatIDs).Error; err != nil {
+		return nil, err
+	}
+
+	taken := make(map[uint]bool, len(seatIDs))
+	for _, id := range seatIDs {
+		taken[id] = true
+	}
+	return taken, nil
+}
+
+// attachAccessibilityCounts fills in accessible seat totals and availability for screenings
+func attachAccessibilityCounts(screenings []models.Screening) error {
+	if len(screenings) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(screenings))
+	for i, screening := range screenings {
+		ids[i] = screening.ID
+	}
+
+	var rows []struct {
+		ScreeningID uint
+		Total       int
+		Taken       int
+	}
+	if err := database.DB.Table("screenings").
+		// Blocked seats can't be booked, so they count as taken like on the seat map
+		Select("screenings.id AS screening_id, COUNT(seats.id) AS total, "+
+			"COUNT(CASE WHEN tickets.id IS NOT NULL OR seats.status = 'blocked' THEN 1 END) AS taken").
+		Joins("JOIN seats ON seats.screen_id = screenings.screen_id AND seats.is_accessible = ?", true).
+		Joins("LEFT JOIN tickets ON tickets.seat_id = seats.id AND tickets.screening_id = screenings.id AND tickets.status <> ?", models.TicketCancelled).
+		Where("screenings.id IN ?", ids).
+		Group("screenings.id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	byScreening := make(map[uint]int, len(rows))
+	for i, row := range rows {
+		byScreening[row.ScreeningID] = i
+	}
+
+	for i := range screenings {
+		accessibility := &models.ScreeningAccessibility{}
+		if idx, ok := byScreening[screenings[i].ID]; ok {
+			accessibility.TotalSeats = rows[idx].Total
+			accessibility.AvailableSeats = rows[idx].Total - rows[idx].Taken
+		}
+		if releaseAt := accessibleSeatReleaseTime(screenings[i]); accessibility.TotalSeats > 0 && time.Now().Before(releaseAt) {
+			accessibility.ReservedUntil = &releaseAt
+		}
+		screenings[i].Accessibility = accessibility
+	}
+
+	return nil
+}
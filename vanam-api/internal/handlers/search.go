@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+)
+
+const (
+	searchDefaultLimit       = 20
+	searchMaxLimit           = 50
+	autocompleteDefaultLimit = 10
+)
+
+// Full-text document for movies, matching idx_movies_search_tsv
+const movieSearchDocument = "to_tsvector('simple', coalesce(m.original_title, '') || ' ' || coalesce(m.description, ''))"
+
+// Movies match on their original title and description, any localized title and their
+// cast. Full-text hits get a flat boost on top of the best trigram similarity.
+const searchMoviesSQL = `
+SELECT m.id, m.original_title AS title, to_char(m.release_date, 'YYYY') AS subtitle, m.poster_url AS image_url,
+	GREATEST(
+		word_similarity(@q, lower(m.original_title)),
+		coalesce((SELECT MAX(word_similarity(@q, lower(ml.title))) FROM movie_languages ml WHERE ml.movie_id = m.id), 0),
+		0.6 * coalesce((SELECT MAX(word_similarity(@q, lower(p.name))) FROM movie_cast mc JOIN people p ON p.id = mc.person_id WHERE mc.movie_id = m.id), 0)
+	) + CASE WHEN ` + movieSearchDocument + ` @@ plainto_tsquery('simple', @q)
+		OR EXISTS (SELECT 1 FROM movie_languages ml WHERE ml.movie_id = m.id AND to_tsvector('simple', coalesce(ml.title, '')) @@ plainto_tsquery('simple', @q))
+		THEN 0.5 ELSE 0 END AS score
+FROM movies m
+WHERE m.is_active AND m.deleted_at IS NULL AND (
+	@q <% lower(m.original_title)
+	OR ` + movieSearchDocument + ` @@ plainto_tsquery('simple', @q)
+	OR EXISTS (SELECT 1 FROM movie_languages ml WHERE ml.movie_id = m.id AND (
+		@q <% lower(ml.title) OR to_tsvector('simple', coalesce(ml.title, '')) @@ plainto_tsquery('simple', @q)))
+	OR EXISTS (SELECT 1 FROM movie_cast mc JOIN people p ON p.id = mc.person_id WHERE mc.movie_id = m.id AND @q <% lower(p.name))
+)
+ORDER BY score DESC, m.release_date DESC
+LIMIT @limit`
+
+const searchPeopleSQL = `
+SELECT p.id, p.name AS title, word_similarity(@q, lower(p.name)) AS score
+FROM people p
+WHERE @q <% lower(p.name)
+ORDER BY score DESC, p.name
+LIMIT @limit`
+
+const searchTheatersSQL = `
+SELECT t.id, t.name AS title, t.city AS subtitle,
+	GREATEST(word_similarity(@q, lower(t.name)), 0.8 * word_similarity(@q, lower(t.city))) AS score
+FROM theaters t
+WHERE t.is_active AND (@q <% lower(t.name) OR @q <% lower(t.city))
+ORDER BY score DESC, t.name
+LIMIT @limit`
+
+// Titles and names starting with the prefix, or with a word starting with it
+const autocompleteSQL = `
+SELECT type, id, text FROM (
+	SELECT DISTINCT ON (type, id, text) type, id, text, starts FROM (
+		SELECT 'movie' AS type, m.id, m.original_title AS text, lower(m.original_title) LIKE @prefix AS starts
+		FROM movies m
+		WHERE m.is_active AND m.deleted_at IS NULL AND (lower(m.original_title) LIKE @prefix OR lower(m.original_title) LIKE @word_prefix)
+		UNION ALL
+		SELECT 'movie', m.id, ml.title, lower(ml.title) LIKE @prefix
+		FROM movie_languages ml JOIN movies m ON m.id = ml.movie_id
+		WHERE m.is_active AND m.deleted_at IS NULL AND (lower(ml.title) LIKE @prefix OR lower(ml.title) LIKE @word_prefix)
+		UNION ALL
+		SELECT 'person', p.id, p.name, lower(p.name) LIKE @prefix
+		FROM people p
+		WHERE lower(p.name) LIKE @prefix OR lower(p.name) LIKE @word_prefix
+		UNION ALL
+		SELECT 'theater', t.id, t.name, lower(t.name) LIKE @prefix
+		FROM theaters t
+		WHERE t.is_active AND (lower(t.name) LIKE @prefix OR lower(t.name) LIKE @word_prefix)
+	) matches
+) suggestions
+ORDER BY starts DESC, length(text), text
+LIMIT @limit`
+
+// Without pg_trgm, movies match on full text or a title, localized title or cast name
+// containing the query, and people and theaters on their names containing it
+const likeSearchMoviesSQL = `
+SELECT m.id, m.original_title AS title, to_char(m.release_date, 'YYYY') AS subtitle, m.poster_url AS image_url,
+	CASE WHEN lower(m.original_title) LIKE @like OR EXISTS (SELECT 1 FROM movie_languages ml WHERE ml.movie_id = m.id AND lower(ml.title) LIKE @like)
+		THEN 1 ELSE 0 END
+	+ CASE WHEN ` + movieSearchDocument + ` @@ plainto_tsquery('simple', @q)
+		OR EXISTS (SELECT 1 FROM movie_languages ml WHERE ml.movie_id = m.id AND to_tsvector('simple', coalesce(ml.title, '')) @@ plainto_tsquery('simple', @q))
+		THEN 0.5 ELSE 0 END
+	+ CASE WHEN EXISTS (SELECT 1 FROM movie_cast mc JOIN people p ON p.id = mc.person_id WHERE mc.movie_id = m.id AND lower(p.name) LIKE @like)
+		THEN 0.6 ELSE 0 END AS score
+FROM movies m
+WHERE m.is_active AND m.deleted_at IS NULL AND (
+	lower(m.original_title) LIKE @like
+	OR ` + movieSearchDocument + ` @@ plainto_tsquery('simple', @q)
+	OR EXISTS (SELECT 1 FROM movie_languages ml WHERE ml.movie_id = m.id AND (
+		lower(ml.title) LIKE @like OR to_tsvector('simple', coalesce(ml.title, '')) @@ plainto_tsquery('simple', @q)))
+	OR EXISTS (SELECT 1 FROM movie_cast mc JOIN people p ON p.id = mc.person_id WHERE mc.movie_id = m.id AND lower(p.name) LIKE @like)
+)
+ORDER BY score DESC, m.release_date DESC
+LIMIT @limit`
+
+const likeSearchPeopleSQL = `
+SELECT p.id, p.name AS title, 1 AS score
+FROM people p
+WHERE lower(p.name) LIKE @like
+ORDER BY p.name
+LIMIT @limit`
+
+const likeSearchTheatersSQL = `
+SELECT t.id, t.name AS title, t.city AS subtitle, CASE WHEN lower(t.name) LIKE @like THEN 1 ELSE 0.8 END AS score
+FROM theaters t
+WHERE t.is_active AND (lower(t.name) LIKE @like OR lower(t.city) LIKE @like)
+ORDER BY score DESC, t.name
+LIMIT @limit`
+
+var searchQueries = map[string]string{
+	"movie":   searchMoviesSQL,
+	"person":  searchPeopleSQL,
+	"theater": searchTheatersSQL,
+}
+
+var likeSearchQueries = map[string]string{
+	"movie":   likeSearchMoviesSQL,
+	"person":  likeSearchPeopleSQL,
+	"theater": likeSearchTheatersSQL,
+}
+
+// Search - Relevance ranked search across movies, people and theaters
+func Search(c *gin.Context) {
+	q := normalizeSearchQuery(c.Query("q"))
+	if len([]rune(q)) < 2 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Search query must be at least 2 characters"))
+		return
+	}
+
+	limit := searchLimit(c, searchDefaultLimit)
+
+	types := []string{"movie", "person", "theater"}
+	if requested := c.Query("types"); requested != "" {
+		types = strings.Split(requested, ",")
+		for _, t := range types {
+			if _, ok := searchQueries[t]; !ok {
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse("Unknown search type: "+t))
+				return
+			}
+		}
+	}
+
+	queries := searchQueries
+	args := []interface{}{sql.Named("q", q), sql.Named("limit", limit)}
+	if !database.TrigramSearch {
+		queries = likeSearchQueries
+		args = append(args, sql.Named("like", "%"+escapeLike(q)+"%"))
+	}
+
+	results := []dtos.SearchResult{}
+	for _, t := range types {
+		var hits []dtos.SearchResult
+		if err := database.DB.Raw(queries[t], args...).Scan(&hits).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Search failed"))
+			return
+		}
+		for i := range hits {
+			hits[i].Type = t
+		}
+		results = append(results, hits...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Search results retrieved successfully", gin.H{
+		"query":   q,
+		"results": results,
+	}))
+}
+
+// SearchAutocomplete - Prefix suggestions for a search box
+func SearchAutocomplete(c *gin.Context) {
+	q := normalizeSearchQuery(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusOK, utils.SuccessResponse("Suggestions retrieved successfully", []dtos.SearchSuggestion{}))
+		return
+	}
+
+	escaped := escapeLike(q)
+	suggestions := []dtos.SearchSuggestion{}
+	if err := database.DB.Raw(autocompleteSQL,
+		sql.Named("prefix", escaped+"%"),
+		sql.Named("word_prefix", "% "+escaped+"%"),
+		sql.Named("limit", searchLimit(c, autocompleteDefaultLimit)),
+	).Scan(&suggestions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch suggestions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Suggestions retrieved successfully", suggestions))
+}
+
+// normalizeSearchQuery lowercases and collapses whitespace so it compares against the
+// lower() expressions the trigram indexes are built on
+func normalizeSearchQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+func searchLimit(c *gin.Context, fallback int) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	if limit > searchMaxLimit {
+		return searchMaxLimit
+	}
+	return limit
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+)
+
+// GetSecurityEvents - Get the authentication audit log with filters (admin only)
+func GetSecurityEvents(c *gin.Context) {
+	var events []models.SecurityEvent
+	query := database.DB.Model(&models.SecurityEvent{})
+
+	// Pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset := (page - 1) * limit
+
+	if eventType := c.Query("type"); eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if email := c.Query("email"); email != "" {
+		query = query.Where("email = ?", email)
+	}
+	if ip := c.Query("ip"); ip != "" {
+		query = query.Where("ip_address = ?", ip)
+	}
+	if since := c.Query("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("since must be an RFC3339 timestamp"))
+			return
+		}
+		query = query.Where("created_at >= ?", sinceTime)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch security events"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginationResponse("Security events retrieved successfully", events, page, limit, total))
+}
+
+// UnlockUser - Lift a login lockout on a user account (admin only)
+func UnlockUser(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	unlocked, err := security.Unlock(user.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to unlock user"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventAccountUnlocked, &user.ID, user.Email, "")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("User unlocked successfully", gin.H{
+		"user_id":    user.ID,
+		"was_locked": unlocked,
+	}))
+}
+
+// recordSecurityEvent appends to the audit log. The acting user, if any, is taken from the context.
+func recordSecurityEvent(c *gin.Context, eventType models.SecurityEventType, userID *uint, email, details string) {
+	event := models.SecurityEvent{
+		Type:      eventType,
+		UserID:    userID,
+		Email:     email,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details:   details,
+	}
+	// Under impersonation the support agent is the one acting
+	if actorID := c.GetUint("impersonator_id"); actorID != 0 {
+		event.ActorID = &actorID
+	} else if actorID := c.GetUint("user_id"); actorID != 0 {
+		event.ActorID = &actorID
+	}
+
+	if err := database.DB.Create(&event).Error; err != nil {
+		log.Printf("Failed to record security event %s: %v", eventType, err)
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetMySessions - List the current user's active sessions
+func GetMySessions(c *gin.Context) {
+	list, err := sessions.ListForUser(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch sessions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Sessions retrieved successfully", sessionList(list, c.GetString("session_id"))))
+}
+
+// RevokeMySession - Log out one of the current user's sessions
+func RevokeMySession(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	list, err := sessions.ListForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch sessions"))
+		return
+	}
+
+	session := findSessionByHandle(list, c.Param("session_id"))
+	if session == nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Session not found"))
+		return
+	}
+
+	if err := sessions.Delete(session); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to revoke session"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Session revoked successfully", nil))
+}
+
+// RevokeMyOtherSessions - Log out every session of the current user except this one
+func RevokeMyOtherSessions(c *gin.Context) {
+	revoked, err := sessions.RevokeAll(c.GetUint("user_id"), c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to revoke sessions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Other sessions revoked successfully", gin.H{
+		"sessions_revoked": revoked,
+	}))
+}
+
+// GetUserSessions - List a user's active sessions (admin only)
+func GetUserSessions(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	list, err := sessions.ListForUser(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch sessions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Sessions retrieved successfully", sessionList(list, c.GetString("session_id"))))
+}
+
+// ForceLogoutUser - End every session of a user immediately (admin only)
+func ForceLogoutUser(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := sessions.RevokeAll(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to log out user"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventSessionsRevoked, &user.ID, user.Email, fmt.Sprintf("%d sessions revoked by admin", revoked))
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("User logged out successfully", gin.H{
+		"user_id":          user.ID,
+		"sessions_revoked": revoked,
+	}))
+}
+
+// RevokeUserSession - End one session of a user (admin only)
+func RevokeUserSession(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	list, err := sessions.ListForUser(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch sessions"))
+		return
+	}
+
+	session := findSessionByHandle(list, c.Param("session_id"))
+	if session == nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Session not found"))
+		return
+	}
+
+	if err := sessions.Delete(session); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to revoke session"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Session revoked successfully", nil))
+}
+
+// sessionList formats sessions for display without exposing their tokens
+func sessionList(list []models.Session, currentSessionID string) []gin.H {
+	result := []gin.H{}
+	for _, session := range list {
+		result = append(result, gin.H{
+			"id":           sessions.Handle(session.SessionID),
+			"device":       session.Device,
+			"user_agent":   session.UserAgent,
+			"ip_address":   session.IPAddress,
+			"created_at":   session.CreatedAt,
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.SessionID == currentSessionID,
+		})
+	}
+	return result
+}
+
+func findSessionByHandle(list []models.Session, handle string) *models.Session {
+	for i := range list {
+		if sessions.Handle(list[i].SessionID) == handle {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// loadSessionUser loads the user named in the route, writing the error response itself
+func loadSessionUser(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID"))
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("User not found"))
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Database error"))
+		return nil, false
+	}
+
+	return &user, true
+}
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/dtos"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	totpIssuer        = "Vanam"
+	recoveryCodeCount = 10
+)
+
+// VerifyTwoFactorLogin - Second step of login, trade a challenge and a TOTP or recovery code for a session
+func VerifyTwoFactorLogin(c *gin.Context) {
+	var req dtos.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("code or recovery_code is required"))
+		return
+	}
+
+	challenge, err := sessions.GetChallenge(req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Verification expired, please log in again"))
+		return
+	}
+
+	if check := security.CheckLogin(challenge.Email, c.ClientIP()); check.Block != security.LoginAllowed {
+		sessions.DeleteChallenge(challenge.Token)
+		rejectLogin(c, challenge.Email, check)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Preload("Role").Where("id = ? AND is_active = ?", challenge.UserID, true).First(&user).Error; err != nil || !user.TOTPEnabled {
+		sessions.DeleteChallenge(challenge.Token)
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Verification expired, please log in again"))
+		return
+	}
+
+	verified := false
+	usedRecoveryCode := false
+	if req.Code != "" {
+		verified = verifyTOTPCode(&user, req.Code)
+	} else {
+		verified = consumeRecoveryCode(user.ID, req.RecoveryCode)
+		usedRecoveryCode = verified
+	}
+
+	if !verified {
+		countLoginFailure(c, models.EventTwoFactorFailed, user.Email, &user.ID)
+		if err := sessions.FailChallenge(challenge); err == sessions.ErrChallengeExhausted {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Too many invalid codes, please log in again"))
+			return
+		}
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid verification code"))
+		return
+	}
+
+	sessions.DeleteChallenge(challenge.Token)
+	if usedRecoveryCode {
+		recordSecurityEvent(c, models.EventRecoveryCodeUsed, &user.ID, user.Email, "")
+	}
+
+	completeLogin(c, user)
+}
+
+// GetTwoFactorStatus - Get the logged-in user's two-factor setup
+func GetTwoFactorStatus(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var remaining int64
+	database.DB.Model(&models.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", user.ID).Count(&remaining)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Two-factor status retrieved successfully", gin.H{
+		"enabled":                  user.TOTPEnabled,
+		"enabled_at":               user.TOTPEnabledAt,
+		"required":                 user.Role.RequireTwoFactor,
+		"recovery_codes_remaining": remaining,
+	}))
+}
+
+// EnrollTwoFactor - Start TOTP enrollment and return the secret and provisioning URI
+func EnrollTwoFactor(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Two-factor authentication is already enabled"))
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to generate secret"))
+		return
+	}
+
+	// Enrollment only takes effect once a code from the app is confirmed
+	if err := database.DB.Model(&user).Updates(map[string]interface{}{
+		"totp_secret":       secret,
+		"totp_last_counter": 0,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to start enrollment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Scan the provisioning URI with an authenticator app, then confirm a code", gin.H{
+		"secret":           secret,
+		"provisioning_uri": utils.TOTPProvisioningURI(secret, user.Email, totpIssuer),
+		"digits":           utils.TOTPDigits,
+		"period":           int(utils.TOTPPeriod.Seconds()),
+	}))
+}
+
+// EnableTwoFactor - Confirm enrollment with a code and receive recovery codes
+func EnableTwoFactor(c *gin.Context) {
+	var req dtos.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Two-factor authentication is already enabled"))
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Start enrollment first"))
+		return
+	}
+
+	if !verifyTOTPCode(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid verification code"))
+		return
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled":    true,
+			"totp_enabled_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to enable two-factor authentication"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventTwoFactorEnabled, &user.ID, user.Email, "")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Two-factor authentication enabled. Store the recovery codes somewhere safe, they are shown only once.", gin.H{
+		"recovery_codes": codes,
+	}))
+}
+
+// DisableTwoFactor - Turn off two-factor authentication for the logged-in user
+func DisableTwoFactor(c *gin.Context) {
+	var req dtos.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Two-factor authentication is not enabled"))
+		return
+	}
+	if user.Role.RequireTwoFactor {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Your role requires two-factor authentication"))
+		return
+	}
+
+	if !utils.CheckPasswordHash(req.Password, user.Password) || !verifyTOTPCode(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid password or verification code"))
+		return
+	}
+
+	if err := clearTwoFactor(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to disable two-factor authentication"))
+		return
+	}
+
+	recordSecurityEvent(c, models.EventTwoFactorDisabled, &user.ID, user.Email, "")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Two-factor authentication disabled", nil))
+}
+
+// RegenerateRecoveryCodes - Replace the logged-in user's recovery codes
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req dtos.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Two-factor authentication is not enabled"))
+		return
+	}
+
+	if !verifyTOTPCode(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid verification code"))
+		return
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to generate recovery codes"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Recovery codes regenerated", gin.H{
+		"recovery_codes": codes,
+	}))
+}
+
+// ResetUserTwoFactor - Remove a user's second factor so they can enroll again (admin only)
+func ResetUserTwoFactor(c *gin.Context) {
+	user, ok := loadSessionUser(c)
+	if !ok {
+		return
+	}
+
+	if err := clearTwoFactor(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to reset two-factor authentication"))
+		return
+	}
+
+	revoked, _ := sessions.RevokeAll(user.ID)
+	recordSecurityEvent(c, models.EventTwoFactorDisabled, &user.ID, user.Email, "reset by admin")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Two-factor authentication reset successfully", gin.H{
+		"user_id":          user.ID,
+		"sessions_revoked": revoked,
+	}))
+}
+
+// verifyTOTPCode checks a code and claims its time step, so each code works only once
+func verifyTOTPCode(user *models.User, code string) bool {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return false
+	}
+
+	result := database.DB.Model(&models.User{}).
+		Where("id = ? AND totp_last_counter < ?", user.ID, step).
+		Update("totp_last_counter", step)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false
+	}
+
+	user.TOTPLastCounter = step
+	return true
+}
+
+// consumeRecoveryCode marks an unused recovery code as used
+func consumeRecoveryCode(userID uint, code string) bool {
+	result := database.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, utils.HashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// replaceRecoveryCodes drops a user's recovery codes and issues a new set
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]models.RecoveryCode, len(codes))
+	for i, code := range codes {
+		records[i] = models.RecoveryCode{UserID: userID, CodeHash: utils.HashRecoveryCode(code)}
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// clearTwoFactor removes a user's TOTP secret and recovery codes
+func clearTwoFactor(userID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":       "",
+			"totp_enabled":      false,
+			"totp_enabled_at":   nil,
+			"totp_last_counter": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
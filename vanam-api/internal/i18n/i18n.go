@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+)
+
+var (
+	fallbacksOnce sync.Once
+	fallbacks     map[string][]string
+)
+
+// Normalize lowercases a language tag and uses '-' as the subtag separator, so "ta_IN"
+// and "ta-in" compare equal
+func Normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// ParseAcceptLanguage returns the tags in an Accept-Language header, most preferred first.
+// Wildcards and tags with q=0 are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := Normalize(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{tag, q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	tags := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		tags = append(tags, entry.tag)
+	}
+	return tags
+}
+
+// Chain expands the requested languages into the order content should be looked up in:
+// each tag, then its base language, then its configured fallbacks, and finally the
+// default language
+func Chain(requested ...string) []string {
+	fallbacksOnce.Do(loadFallbacks)
+
+	chain := []string{}
+	seen := make(map[string]bool)
+	var add func(tag string)
+	add = func(tag string) {
+		tag = Normalize(tag)
+		if tag == "" || seen[tag] {
+			return
+		}
+		seen[tag] = true
+		chain = append(chain, tag)
+
+		if i := strings.Index(tag, "-"); i > 0 {
+			add(tag[:i])
+		}
+		for _, next := range fallbacks[tag] {
+			add(next)
+		}
+	}
+
+	for _, tag := range requested {
+		add(tag)
+	}
+	add(config.App.DefaultLanguage)
+	return chain
+}
+
+// loadFallbacks parses LANGUAGE_FALLBACKS, e.g. "ml:ta,en;te:en"
+func loadFallbacks() {
+	fallbacks = make(map[string][]string)
+	for _, rule := range strings.Split(config.App.LanguageFallbacks, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(rule, ":", 2)
+		if len(parts) != 2 {
+			log.Printf("❌ Ignoring malformed language fallback rule %q", rule)
+			continue
+		}
+
+		from := Normalize(parts[0])
+		for _, to := range strings.Split(parts[1], ",") {
+			if to = Normalize(to); to != "" {
+				fallbacks[from] = append(fallbacks[from], to)
+			}
+		}
+	}
+}
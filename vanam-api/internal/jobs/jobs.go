@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Every runs fn in the background on a fixed interval, starting immediately
+func Every(name string, interval time.Duration, fn func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := fn(); err != nil {
+				log.Printf("❌ Job %s failed: %v", name, err)
+			}
+			<-ticker.C
+		}
+	}()
+
+	log.Printf("⏱️  Scheduled job %s every %s", name, interval)
+}
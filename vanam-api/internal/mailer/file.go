@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// FileMailer writes every message to Dir as an .eml file, so flows can be tested
+// without a mail server
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (FileMailer) Name() string {
+	return "file"
+}
+
+func (m FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(m.Dir, name)
+
+	if err := os.WriteFile(path, buildMessage(m.From, msg), 0o600); err != nil {
+		return err
+	}
+
+	log.Printf("📧 Mail to %s written to %s", msg.To, path)
+	return nil
+}
@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"regexp"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string // Plain text
+}
+
+// Mailer is implemented by every outgoing mail transport
+type Mailer interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+var Default Mailer
+
+func Setup(cfg *config.Config) {
+	switch cfg.MailDriver {
+	case "", "log":
+		Default = LogMailer{}
+	case "file":
+		Default = FileMailer{Dir: cfg.MailDir, From: cfg.MailFrom}
+	case "smtp":
+		Default = SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}
+	default:
+		log.Fatalf("Unknown mail driver: %s", cfg.MailDriver)
+	}
+
+	log.Printf("✅ Mail driver: %s", Default.Name())
+}
+
+// LogMailer prints messages to the log instead of sending them, for local development.
+// Password reset and verification tokens are redacted, since logs are shipped and kept
+// where anyone reading them could take over the account; use the file driver to follow
+// the links locally.
+type LogMailer struct{}
+
+// Token query parameters in links, as built by the account email handlers
+var linkToken = regexp.MustCompile(`([?&]token=)[^&\s]+`)
+
+func (LogMailer) Name() string {
+	return "log"
+}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("📧 Mail to %s: %s\n%s", msg.To, msg.Subject, linkToken.ReplaceAllString(msg.Body, "${1}[redacted]"))
+	return nil
+}
@@ -0,0 +1,117 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register the WebP decoder with image.Decode
+)
+
+// Larger images are refused before decoding, so a small file can't expand into gigabytes
+const maxImagePixels = 40_000_000
+
+var ErrUnsupportedImage = errors.New("unsupported image type, upload a JPEG, PNG or WebP file")
+
+var imageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// SniffImage works out the content type from the file's bytes, ignoring whatever the
+// client claimed
+func SniffImage(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if !imageContentTypes[contentType] {
+		return contentType, ErrUnsupportedImage
+	}
+	return contentType, nil
+}
+
+// Rendition is one encoded size and format of an uploaded image
+type Rendition struct {
+	Name        string // "original", or the size name such as "w342"
+	Format      string // "jpeg", "png" or "webp"
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// ProcessImage decodes an uploaded image and renders it at each width narrower than the
+// original, as JPEG (PNG when it has transparency) and as WebP. WebP output is lossless,
+// as that is what can be encoded without cgo.
+func ProcessImage(data []byte, widths []int) (image.Config, []Rendition, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return config, nil, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return config, nil, fmt.Errorf("image is %dx%d, at most %d megapixels are allowed", config.Width, config.Height, maxImagePixels/1_000_000)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return config, nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	var renditions []Rendition
+	for _, width := range widths {
+		if width >= config.Width {
+			continue
+		}
+		height := config.Height * width / config.Width
+		if height < 1 {
+			height = 1
+		}
+
+		scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+		name := fmt.Sprintf("w%d", width)
+		encoded, err := encodeRenditions(name, scaled)
+		if err != nil {
+			return config, nil, err
+		}
+		renditions = append(renditions, encoded...)
+	}
+
+	return config, renditions, nil
+}
+
+func encodeRenditions(name string, img *image.RGBA) ([]Rendition, error) {
+	bounds := img.Bounds()
+	base := Rendition{Name: name, Width: bounds.Dx(), Height: bounds.Dy()}
+
+	var buf bytes.Buffer
+	flat := base
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		flat.Format, flat.ContentType = "jpeg", "image/jpeg"
+	} else {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		flat.Format, flat.ContentType = "png", "image/png"
+	}
+	flat.Data = append([]byte(nil), buf.Bytes()...)
+
+	buf.Reset()
+	if err := nativewebp.Encode(&buf, img, nil); err != nil {
+		return nil, err
+	}
+	webp := base
+	webp.Format, webp.ContentType = "webp", "image/webp"
+	webp.Data = append([]byte(nil), buf.Bytes()...)
+
+	return []Rendition{flat, webp}, nil
+}
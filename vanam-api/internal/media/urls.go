@@ -0,0 +1,71 @@
+package media
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+)
+
+// Init sets the URL signing key, the public base URL media is served from and how long
+// signed URLs stay valid. Without a key a random one is used, so URLs don't survive a
+// restart.
+func Init(key, publicURL string, urlTTL time.Duration) {
+	baseURL = strings.TrimRight(publicURL, "/")
+	ttl = urlTTL
+
+	if key != "" {
+		secret = []byte(key)
+		return
+	}
+
+	secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal("Failed to generate media signing key:", err)
+	}
+	log.Println("⚠️  MEDIA_SIGNING_KEY not set, using an ephemeral key")
+}
+
+// SignedURL returns a URL for a stored object. Expiry is rounded up to a window of half
+// the TTL, so the same object keeps the same URL for a while and caches can reuse it.
+func SignedURL(key string) string {
+	window := int64(ttl/time.Second) / 2
+	if window < 1 {
+		window = 1
+	}
+	expires := (time.Now().Unix()/window + 2) * window
+
+	return fmt.Sprintf("%s/api/v1/media/%s?expires=%d&sig=%s", baseURL, key, expires, sign(key, expires))
+}
+
+// Verify checks a signed URL's key, expiry and signature. It returns when the URL
+// expires, for caching headers.
+func Verify(key, expiresParam, sig string) (time.Time, bool) {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	expiresAt := time.Unix(expires, 0)
+	if time.Now().After(expiresAt) {
+		return expiresAt, false
+	}
+	return expiresAt, hmac.Equal([]byte(sig), []byte(sign(key, expires)))
+}
+
+func sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%d", key, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,8 +1,6 @@
 package middleware
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,9 +9,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prabalesh/vanam/vanam-api/internal/database"
 	"github.com/prabalesh/vanam/vanam-api/internal/models"
-	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+	"github.com/prabalesh/vanam/vanam-api/internal/security"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
 )
 
+// Routes a user can still reach while their account setup is incomplete
+var accountSetupPaths = map[string]bool{
+	"/api/admin/v1/profile":             true,
+	"/api/admin/v1/logout":              true,
+	"/api/admin/v1/change-password":     true,
+	"/api/admin/v1/verify-email/resend": true,
+	"/api/admin/v1/2fa":                 true,
+	"/api/admin/v1/2fa/enroll":          true,
+	"/api/admin/v1/2fa/enable":          true,
+}
+
 // AdminAuthMiddleware - Your existing admin authentication middleware (unchanged)
 func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -34,7 +44,7 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 		// Verify admin user
 		var user models.User
 		if err := database.DB.Preload("Role").First(&user, session.UserID).Error; err != nil {
-			deleteSession(sessionID)
+			deleteSession(session)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin user not found"})
 			c.Abort()
 			return
@@ -47,7 +57,7 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Extend admin session
-		extendSession(sessionID, session)
+		extendSession(c, session)
 
 		c.Set("admin_id", session.UserID)
 		c.Set("session_id", sessionID)
@@ -56,9 +66,22 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-// UserAuthMiddleware - General user authentication middleware for getUserDetails and other user operations
-func UserAuthMiddleware() gin.HandlerFunc {
+// UserAuthMiddleware - General user authentication middleware for getUserDetails and other user operations.
+// API keys are accepted only where scopes are given, and must hold all of them.
+func UserAuthMiddleware(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if extractAPIKey(c) != "" {
+			if len(scopes) == 0 {
+				c.JSON(http.StatusForbidden, gin.H{"error": "API keys are not accepted for this endpoint"})
+				c.Abort()
+				return
+			}
+			if authenticateAPIKey(c, scopes...) {
+				c.Next()
+			}
+			return
+		}
+
 		sessionID := extractSessionToken(c)
 		if sessionID == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
@@ -77,7 +100,7 @@ func UserAuthMiddleware() gin.HandlerFunc {
 		// Verify user exists and is active
 		var user models.User
 		if err := database.DB.Preload("Role").First(&user, session.UserID).Error; err != nil {
-			deleteSession(sessionID)
+			deleteSession(session)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 			c.Abort()
 			return
@@ -89,14 +112,38 @@ func UserAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		impersonating := session.ImpersonatorID != 0
+		if impersonating {
+			// Every request is audited, including the ones refused below
+			defer auditImpersonatedRequest(c, session)
+			if !allowImpersonatedRequest(c, session) {
+				return
+			}
+		}
+
+		if (user.MustChangePassword || security.PasswordExpired(user)) && !accountSetupPaths[c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Password change required", "password_change_required": true})
+			c.Abort()
+			return
+		}
+
+		if user.Role.RequireTwoFactor && !user.TOTPEnabled && !accountSetupPaths[c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor enrollment required", "two_factor_enrollment_required": true})
+			c.Abort()
+			return
+		}
+
 		// Extend user session (shorter duration for regular users)
-		extendUserSession(sessionID, session)
+		extendUserSession(c, session)
 
 		// Set context for any authenticated user
 		c.Set("user_id", session.UserID)
 		c.Set("session_id", sessionID)
 		c.Set("user_role", user.Role)
 		c.Set("user", user) // Full user object available in context
+		if impersonating {
+			c.Set("impersonator_id", session.ImpersonatorID)
+		}
 		c.Next()
 	}
 }
@@ -117,37 +164,18 @@ func extractSessionToken(c *gin.Context) string {
 }
 
 func validateSession(sessionID string) (*models.Session, error) {
-	sessionData, err := redis.Client.Get(redis.Ctx, "session:"+sessionID).Result()
-	if err != nil {
-		return nil, err
-	}
-
-	var session models.Session
-	if err := json.Unmarshal([]byte(sessionData), &session); err != nil {
-		return nil, err
-	}
-
-	if time.Now().After(session.ExpiresAt) {
-		redis.Client.Del(redis.Ctx, "session:"+sessionID)
-		return nil, errors.New("session expired")
-	}
-
-	return &session, nil
+	return sessions.Get(sessionID)
 }
 
-func extendSession(sessionID string, session *models.Session) {
-	session.ExpiresAt = time.Now().Add(24 * time.Hour)
-	updatedSessionJSON, _ := json.Marshal(session)
-	redis.Client.Set(redis.Ctx, "session:"+sessionID, updatedSessionJSON, 24*time.Hour)
+func extendSession(c *gin.Context, session *models.Session) {
+	sessions.Touch(session, 24*time.Hour, c.ClientIP())
 }
 
 // New helper function for regular user session extension (shorter duration)
-func extendUserSession(sessionID string, session *models.Session) {
-	session.ExpiresAt = time.Now().Add(8 * time.Hour) // Regular users get 8 hours
-	updatedSessionJSON, _ := json.Marshal(session)
-	redis.Client.Set(redis.Ctx, "session:"+sessionID, updatedSessionJSON, 8*time.Hour)
+func extendUserSession(c *gin.Context, session *models.Session) {
+	sessions.Touch(session, 8*time.Hour, c.ClientIP()) // Regular users get 8 hours
 }
 
-func deleteSession(sessionID string) {
-	redis.Client.Del(redis.Ctx, "session:"+sessionID)
+func deleteSession(session *models.Session) {
+	sessions.Delete(session)
 }
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/sessions"
+)
+
+// Customer routes a support agent can't use while impersonating. Writes to the admin API
+// are refused wholesale, so password, 2FA and email changes are covered there. Payment
+// method routes belong here once customers can manage them.
+var impersonationBlockedRoutes = map[string]bool{
+	"DELETE /api/v1/sessions":             true,
+	"DELETE /api/v1/sessions/:session_id": true,
+}
+
+// allowImpersonatedRequest checks that an impersonation session is still backed by an
+// active admin and that the request isn't a sensitive action. It writes the error response itself.
+func allowImpersonatedRequest(c *gin.Context, session *models.Session) bool {
+	var agent models.User
+	if err := database.DB.Preload("Role").First(&agent, session.ImpersonatorID).Error; err != nil ||
+		!agent.IsActive || agent.Role.Name != "admin" {
+		sessions.Delete(session)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session"})
+		c.Abort()
+		return false
+	}
+
+	path := c.FullPath()
+	blocked := impersonationBlockedRoutes[c.Request.Method+" "+path] ||
+		(strings.HasPrefix(path, "/api/admin/v1/") && c.Request.Method != http.MethodGet && path != "/api/admin/v1/logout")
+	if blocked {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This action is not allowed while impersonating a user", "impersonating": true})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
+
+// auditImpersonatedRequest records every request made under impersonation
+func auditImpersonatedRequest(c *gin.Context, session *models.Session) {
+	userID, agentID := session.UserID, session.ImpersonatorID
+	event := models.SecurityEvent{
+		Type:      models.EventImpersonatedRequest,
+		UserID:    &userID,
+		ActorID:   &agentID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details:   fmt.Sprintf("%s %s -> %d", c.Request.Method, c.Request.URL.RequestURI(), c.Writer.Status()),
+	}
+
+	if err := database.DB.Create(&event).Error; err != nil {
+		log.Printf("Failed to audit impersonated request: %v", err)
+	}
+}
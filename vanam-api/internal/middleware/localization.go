@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/i18n"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+)
+
+// Localization resolves the languages to serve content in and stores the fallback chain
+// as "languages". An explicit ?lang wins over the signed-in user's preference, which
+// wins over Accept-Language.
+func Localization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requested []string
+		if lang := c.Query("lang"); lang != "" {
+			requested = append(requested, lang)
+		}
+		if preferred := preferredLanguage(c); preferred != "" {
+			requested = append(requested, preferred)
+		}
+		requested = append(requested, i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+
+		c.Set("languages", i18n.Chain(requested...))
+		// The signed-in user's preference changes the response too, so caches must key on
+		// the credentials as well as Accept-Language
+		c.Writer.Header().Add("Vary", "Accept-Language, Authorization, Cookie")
+		c.Next()
+	}
+}
+
+// preferredLanguage looks up the language preference of a signed-in user. Public routes
+// don't require a session, so one is only used if the request happens to carry it.
+func preferredLanguage(c *gin.Context) string {
+	if user, ok := c.Get("user"); ok {
+		return user.(models.User).PreferredLanguage
+	}
+	if extractAPIKey(c) != "" {
+		return ""
+	}
+
+	sessionID := extractSessionToken(c)
+	if sessionID == "" {
+		return ""
+	}
+	session, err := validateSession(sessionID)
+	if err != nil {
+		return ""
+	}
+
+	var preferred string
+	database.DB.Model(&models.User{}).Select("preferred_language").Where("id = ?", session.UserID).Scan(&preferred)
+	return preferred
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+)
+
+// RateLimitKeyFunc names the client a request is counted against
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitPolicy allows Limit requests per Window for each client
+type RateLimitPolicy struct {
+	Name   string // Keeps counters of different policies apart
+	Limit  int    // 0 or less disables the policy
+	Window time.Duration
+	KeyBy  RateLimitKeyFunc
+}
+
+// ByIP counts requests per client IP
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser counts requests per logged-in user, falling back to the IP for anonymous requests.
+// It must run after the auth middleware.
+func ByUser(c *gin.Context) string {
+	if userID := c.GetUint("user_id"); userID != 0 {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	return ByIP(c)
+}
+
+// ByClient counts requests per API key, then per user, then per IP
+func ByClient(c *gin.Context) string {
+	if keyID := c.GetUint("api_key_id"); keyID != 0 {
+		return fmt.Sprintf("key:%d", keyID)
+	}
+	return ByUser(c)
+}
+
+// RateLimit - Throttle requests with a sliding window shared through Redis
+func RateLimit(policy RateLimitPolicy) gin.HandlerFunc {
+	if policy.KeyBy == nil {
+		policy.KeyBy = ByIP
+	}
+
+	return func(c *gin.Context) {
+		if policy.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		result := allowRequest("ratelimit:"+policy.Name+":"+policy.KeyBy(c), policy.Limit, policy.Window)
+		if !rejectRateLimited(c, result) {
+			c.Next()
+		}
+	}
+}
+
+type rateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Window     time.Duration
+	Reset      time.Duration // Until the current window ends
+	RetryAfter time.Duration // Until a request would be allowed again, when rejected
+}
+
+// rejectRateLimited writes the rate limit headers and, when the request is over the
+// limit, the 429 response. It reports whether the request was rejected.
+func rejectRateLimited(c *gin.Context, result rateLimitResult) bool {
+	setRateLimitHeaders(c, result)
+	if result.Allowed {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please slow down"})
+	c.Abort()
+	return true
+}
+
+// setRateLimitHeaders reports the most restrictive limit when several apply to a request
+func setRateLimitHeaders(c *gin.Context, result rateLimitResult) {
+	if existing := c.Writer.Header().Get("RateLimit-Remaining"); existing != "" {
+		if remaining, err := strconv.Atoi(existing); err == nil && remaining <= result.Remaining {
+			return
+		}
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, ceilSeconds(result.Window)))
+}
+
+// The sliding window is approximated from two fixed windows: the previous window's
+// count is weighted by how much of it still overlaps the sliding window.
+var slidingWindowScript = goredis.NewScript(`
+local previous = tonumber(redis.call('GET', KEYS[1]) or '0')
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+
+if previous * (window - elapsed) / window + current < limit then
+	current = redis.call('INCR', KEYS[2])
+	redis.call('PEXPIRE', KEYS[2], window * 2)
+	return {1, previous, current}
+end
+return {0, previous, current}
+`)
+
+var (
+	memoryLimiter    = newMemoryRateLimiter()
+	degradedMu       sync.Mutex
+	degradedLoggedAt time.Time
+)
+
+// allowRequest counts a request against key. If Redis is unavailable it degrades to
+// per-instance counters, so limits still hold but are no longer shared between servers.
+func allowRequest(key string, limit int, window time.Duration) rateLimitResult {
+	now := time.Now()
+	windowMs := window.Milliseconds()
+	index := now.UnixMilli() / windowMs
+	elapsed := now.UnixMilli() % windowMs
+
+	if redis.Client != nil {
+		keys := []string{
+			fmt.Sprintf("%s:%d", key, index-1),
+			fmt.Sprintf("%s:%d", key, index),
+		}
+		values, err := slidingWindowScript.Run(redis.Ctx, redis.Client, keys, limit, windowMs, elapsed).Int64Slice()
+		if err == nil && len(values) == 3 {
+			return slidingWindowResult(values[0] == 1, values[1], values[2], limit, window, elapsed)
+		}
+		logDegraded(err)
+	}
+
+	allowed, previous, current := memoryLimiter.hit(key, limit, windowMs, index, elapsed, now)
+	return slidingWindowResult(allowed, previous, current, limit, window, elapsed)
+}
+
+func slidingWindowResult(allowed bool, previous, current int64, limit int, window time.Duration, elapsedMs int64) rateLimitResult {
+	w := float64(window.Milliseconds())
+	e := float64(elapsedMs)
+	count := float64(previous)*(w-e)/w + float64(current)
+
+	result := rateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Max(0, math.Floor(float64(limit)-count))),
+		Window:    window,
+		Reset:     time.Duration(w-e) * time.Millisecond,
+	}
+
+	if !allowed {
+		var wait float64
+		if current < int64(limit) && previous > 0 {
+			// Wait for enough of the previous window to slide out
+			wait = (w - e) - float64(int64(limit)-current)*w/float64(previous)
+		} else {
+			// The current window alone is full, so wait until it has slid far enough
+			wait = (w - e) + w*(1-float64(limit)/float64(current))
+		}
+		result.RetryAfter = time.Duration(math.Max(wait, 0)) * time.Millisecond
+	}
+	return result
+}
+
+func logDegraded(err error) {
+	degradedMu.Lock()
+	defer degradedMu.Unlock()
+
+	if time.Since(degradedLoggedAt) > time.Minute {
+		degradedLoggedAt = time.Now()
+		log.Printf("⚠️  Rate limiter can't reach Redis, using in-memory counters: %v", err)
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+type memoryWindow struct {
+	index    int64
+	current  int64
+	previous int64
+	expires  time.Time
+}
+
+type memoryRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	sweptAt time.Time
+}
+
+func newMemoryRateLimiter() *memoryRateLimiter {
+	return &memoryRateLimiter{windows: make(map[string]*memoryWindow)}
+}
+
+func (m *memoryRateLimiter) hit(key string, limit int, windowMs, index, elapsed int64, now time.Time) (bool, int64, int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.sweptAt) > time.Minute {
+		for k, w := range m.windows {
+			if now.After(w.expires) {
+				delete(m.windows, k)
+			}
+		}
+		m.sweptAt = now
+	}
+
+	w, ok := m.windows[key]
+	if !ok {
+		w = &memoryWindow{index: index}
+		m.windows[key] = w
+	}
+	switch {
+	case w.index == index-1:
+		w.index, w.previous, w.current = index, w.current, 0
+	case w.index < index-1:
+		w.index, w.previous, w.current = index, 0, 0
+	}
+
+	allowed := float64(w.previous)*float64(windowMs-elapsed)/float64(windowMs)+float64(w.current) < float64(limit)
+	if allowed {
+		w.current++
+		w.expires = now.Add(2 * time.Duration(windowMs) * time.Millisecond)
+	}
+	return allowed, w.previous, w.current
+}
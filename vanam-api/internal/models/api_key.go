@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// API key scopes
+const (
+	ScopeCatalogRead   = "catalog:read"
+	ScopeBookingsRead  = "bookings:read"
+	ScopeBookingsWrite = "bookings:write" // Implies bookings:read
+	ScopeCheckin       = "checkin"
+)
+
+var APIKeyScopes = []string{ScopeCatalogRead, ScopeBookingsRead, ScopeBookingsWrite, ScopeCheckin}
+
+// APIKey gives a partner or kiosk machine access acting as UserID. Only a hash of the key is stored.
+type APIKey struct {
+	ID                 uint       `json:"id" gorm:"primarykey"`
+	Name               string     `json:"name" gorm:"not null"`
+	Prefix             string     `json:"prefix" gorm:"not null;index"` // Shown so keys can be told apart
+	KeyHash            string     `json:"-" gorm:"not null;uniqueIndex"`
+	UserID             uint       `json:"user_id" gorm:"not null;index"`
+	Scopes             string     `json:"-" gorm:"not null"`                     // Comma separated
+	RateLimitPerMinute int        `json:"rate_limit_per_minute" gorm:"not null"` // 0 means unlimited
+	ExpiresAt          *time.Time `json:"expires_at"`
+	LastUsedAt         *time.Time `json:"last_used_at"`
+	LastUsedIP         string     `json:"last_used_ip"`
+	RevokedAt          *time.Time `json:"revoked_at"`
+	CreatedBy          uint       `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// ScopeList splits the stored scopes
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope reports whether the key grants scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope || (s == ScopeBookingsWrite && scope == ScopeBookingsRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUsable reports whether the key can authenticate right now
+func (k APIKey) IsUsable(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+type BookingStatus string
+
+const (
+	BookingConfirmed BookingStatus = "confirmed"
+	BookingCancelled BookingStatus = "cancelled"
+
+	// The screening was cancelled and the customer may move to another show or take a refund
+	BookingRebookOffered BookingStatus = "rebook_offered"
+)
+
+type TicketStatus string
+
+const (
+	TicketIssued    TicketStatus = "issued"
+	TicketAdmitted  TicketStatus = "admitted"
+	TicketCancelled TicketStatus = "cancelled"
+)
+
+type Booking struct {
+	ID           uint          `json:"id" gorm:"primarykey"`
+	BookingRef   string        `json:"booking_ref" gorm:"uniqueIndex;not null"`
+	UserID       uint          `json:"user_id" gorm:"not null;index"`
+	ScreeningID  uint          `json:"screening_id" gorm:"not null;index"`
+	Status       BookingStatus `json:"status" gorm:"default:'confirmed'"`
+	SeatCount    int           `json:"seat_count" gorm:"not null"`
+	TotalAmount  float64       `json:"total_amount" gorm:"not null"`
+	IsAccessible bool          `json:"is_accessible" gorm:"default:false"` // Customer requested accessible seating
+	CancelledAt  *time.Time    `json:"cancelled_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+
+	// Relationships
+	User      User      `json:"-"`
+	Screening Screening `json:"screening,omitempty"`
+	Tickets   []Ticket  `json:"tickets,omitempty"`
+	Refunds   []Refund  `json:"refunds,omitempty"`
+}
+
+type Ticket struct {
+	ID          uint         `json:"id" gorm:"primarykey"`
+	BookingID   uint         `json:"booking_id" gorm:"not null;index"`
+	ScreeningID uint         `json:"screening_id" gorm:"not null;uniqueIndex:idx_ticket_screening_seat,where:status <> 'cancelled'"`
+	SeatID      uint         `json:"seat_id" gorm:"not null;uniqueIndex:idx_ticket_screening_seat,where:status <> 'cancelled'"`
+	SeatNumber  string       `json:"seat_number" gorm:"not null"`
+	Price       float64      `json:"price" gorm:"not null"`
+	Status      TicketStatus `json:"status" gorm:"default:'issued'"`
+	IsCompanion bool         `json:"is_companion" gorm:"default:false"` // Paired with an accessible seat
+	Code        string       `json:"code" gorm:"type:text"`             // Signed code rendered as a QR on the ticket
+	AdmittedAt  *time.Time   `json:"admitted_at"`
+	AdmittedBy  *uint        `json:"admitted_by"` // Usher who scanned the ticket
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+
+	// Relationships
+	Seat Seat `json:"seat,omitempty"`
+}
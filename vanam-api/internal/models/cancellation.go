@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// CancellationPolicy decides how much of a booking is refunded when it is cancelled.
+// A screening policy wins over a theater policy, which wins over the default policy.
+type CancellationPolicy struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	Name          string    `json:"name" gorm:"not null"`
+	TheaterID     *uint     `json:"theater_id" gorm:"index"`
+	ScreeningID   *uint     `json:"screening_id" gorm:"index"`
+	IsDefault     bool      `json:"is_default" gorm:"default:false"`
+	CutoffMinutes int       `json:"cutoff_minutes" gorm:"default:0"` // No cancellations this close to the show
+	IsActive      bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationships
+	Tiers []CancellationTier `json:"tiers" gorm:"foreignKey:PolicyID;constraint:OnDelete:CASCADE"`
+}
+
+// CancellationTier refunds RefundPercent when cancelling at least HoursBefore the show
+type CancellationTier struct {
+	ID            uint    `json:"id" gorm:"primarykey"`
+	PolicyID      uint    `json:"policy_id" gorm:"not null;index"`
+	HoursBefore   int     `json:"hours_before" gorm:"not null"`
+	RefundPercent float64 `json:"refund_percent" gorm:"not null"`
+}
+
+type BookingCancellation struct {
+	ID              uint      `json:"id" gorm:"primarykey"`
+	BookingID       uint      `json:"booking_id" gorm:"uniqueIndex;not null"`
+	PolicyID        *uint     `json:"policy_id"`
+	CancelledBy     uint      `json:"cancelled_by" gorm:"not null"`
+	Forced          bool      `json:"forced" gorm:"default:false"` // Admin override, policy ignored
+	Reason          string    `json:"reason"`
+	HoursBeforeShow float64   `json:"hours_before_show"`
+	RefundPercent   float64   `json:"refund_percent"`
+	RefundAmount    float64   `json:"refund_amount"`
+	SeatsReleased   int       `json:"seats_released"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type Refund struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	BookingID   uint      `json:"booking_id" gorm:"not null;index"`
+	Amount      float64   `json:"amount" gorm:"not null"`
+	Provider    string    `json:"provider" gorm:"not null"`
+	ProviderRef string    `json:"provider_ref"`
+	Status      string    `json:"status" gorm:"default:'pending'"` // pending, succeeded, failed
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ScreeningCancellation records a cancelled show and the impact report produced for it
+type ScreeningCancellation struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	ScreeningID uint      `json:"screening_id" gorm:"uniqueIndex;not null"`
+	CancelledBy uint      `json:"cancelled_by" gorm:"not null"`
+	Reason      string    `json:"reason" gorm:"not null"`
+	Mode        string    `json:"mode" gorm:"not null"`    // refund, rebook
+	Report      string    `json:"report" gorm:"type:text"` // JSON impact report
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RebookOfferStatus string
+
+const (
+	RebookOfferOpen     RebookOfferStatus = "open"
+	RebookOfferAccepted RebookOfferStatus = "accepted"
+	RebookOfferDeclined RebookOfferStatus = "declined"
+	RebookOfferExpired  RebookOfferStatus = "expired"
+)
+
+// RebookOffer lets a customer of a cancelled screening move to another show of the same movie
+type RebookOffer struct {
+	ID           uint              `json:"id" gorm:"primarykey"`
+	BookingID    uint              `json:"booking_id" gorm:"uniqueIndex;not null"`
+	ScreeningID  uint              `json:"screening_id" gorm:"not null;index"`
+	Status       RebookOfferStatus `json:"status" gorm:"default:'open'"`
+	ExpiresAt    time.Time         `json:"expires_at"`
+	NewBookingID *uint             `json:"new_booking_id"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// CertificationSystem is a country's film classification scheme, e.g. CBFC in India
+type CertificationSystem struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Country   string    `json:"country" gorm:"size:2;uniqueIndex;not null"` // ISO 3166-1 alpha-2, upper case
+	Name      string    `json:"name" gorm:"not null"`
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Certifications []Certification `json:"certifications,omitempty" gorm:"foreignKey:SystemID"`
+}
+
+// Certification is one category of a system. MinAge is the age the category is meant
+// for; only Restricted categories are enforced at booking.
+type Certification struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	SystemID    uint      `json:"system_id" gorm:"not null;uniqueIndex:idx_certification_code"`
+	Code        string    `json:"code" gorm:"not null;uniqueIndex:idx_certification_code"` // "U/A 13+", "A", "PG-13"
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	MinAge      int       `json:"min_age" gorm:"default:0"`
+	Restricted  bool      `json:"restricted" gorm:"default:false"` // Customers must prove they are at least MinAge
+	SortOrder   int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	System *CertificationSystem `json:"system,omitempty" gorm:"foreignKey:SystemID"`
+}
+
+// MovieCertification is the category a movie was given in one country
+type MovieCertification struct {
+	ID                uint      `json:"id" gorm:"primarykey"`
+	MovieID           uint      `json:"movie_id" gorm:"not null;uniqueIndex:idx_movie_certification_system"`
+	SystemID          uint      `json:"system_id" gorm:"not null;uniqueIndex:idx_movie_certification_system"`
+	CertificationID   uint      `json:"certification_id" gorm:"not null"`
+	CertificateNumber string    `json:"certificate_number,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	Certification Certification        `json:"certification"`
+	System        *CertificationSystem `json:"system,omitempty" gorm:"foreignKey:SystemID"`
+}
+
+// ContentAdvisory warns about something in a movie, like "strong violence"
+type ContentAdvisory struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	MovieID     uint      `json:"movie_id" gorm:"not null;index"`
+	Category    string    `json:"category" gorm:"not null"` // violence, language, sexual_content, drugs, frightening, discrimination, flashing_lights
+	Severity    string    `json:"severity" gorm:"not null"` // mild, moderate, strong
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+var AdvisoryCategories = []string{"violence", "language", "sexual_content", "drugs", "frightening", "discrimination", "flashing_lights"}
+
+// Age is how old someone born on dateOfBirth is at the given time
+func Age(dateOfBirth, at time.Time) int {
+	age := at.Year() - dateOfBirth.Year()
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+type MediaKind string
+
+const (
+	MediaPoster   MediaKind = "poster"
+	MediaBackdrop MediaKind = "backdrop"
+	MediaStill    MediaKind = "still"
+	MediaTrailer  MediaKind = "trailer"
+)
+
+// MediaAsset is an image uploaded for a movie, or a trailer hosted elsewhere. Images
+// are kept in storage under Key with resized copies in Variants; trailers only have
+// metadata and a VideoURL.
+type MediaAsset struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	MovieID     uint      `json:"movie_id" gorm:"not null;index"`
+	Kind        MediaKind `json:"kind" gorm:"not null;index"`
+	Key         string    `json:"-"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	Width       int       `json:"width,omitempty"`
+	Height      int       `json:"height,omitempty"`
+	Checksum    string    `json:"checksum,omitempty"` // SHA-256 of the original file
+	IsPrimary   bool      `json:"is_primary" gorm:"default:false"`
+	SortOrder   int       `json:"sort_order" gorm:"default:0"`
+
+	// Trailers
+	Title           string `json:"title,omitempty"`
+	VideoURL        string `json:"video_url,omitempty"`
+	Provider        string `json:"provider,omitempty"` // "youtube", "vimeo" or "other"
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+	LanguageID      *uint  `json:"language_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Variants []MediaVariant `json:"variants,omitempty" gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+
+	// Signed URL of the original, set per request
+	URL string `json:"url,omitempty" gorm:"-"`
+}
+
+// MediaVariant is a resized copy of an image asset
+type MediaVariant struct {
+	ID          uint   `json:"id" gorm:"primarykey"`
+	AssetID     uint   `json:"asset_id" gorm:"not null;index"`
+	Name        string `json:"name"`   // e.g. "w342"
+	Format      string `json:"format"` // "jpeg", "png" or "webp"
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Key         string `json:"-"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+
+	URL string `json:"url,omitempty" gorm:"-"`
+}
+
+// MediaWidths are the widths images of each kind are resized to
+var MediaWidths = map[MediaKind][]int{
+	MediaPoster:   {185, 342, 780},
+	MediaBackdrop: {300, 780, 1280},
+	MediaStill:    {300, 780},
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+type MergeKind string
+
+const (
+	MergeMovie  MergeKind = "movie"
+	MergePerson MergeKind = "person"
+)
+
+// MergeRecord records a duplicate movie or person being merged into a survivor, with
+// what was moved so the merge can be undone
+type MergeRecord struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	Kind        MergeKind  `json:"kind" gorm:"not null;index"`
+	SurvivorID  uint       `json:"survivor_id" gorm:"not null;index"`
+	DuplicateID uint       `json:"duplicate_id" gorm:"not null;index"`
+	MergedBy    uint       `json:"merged_by"`
+	Changes     string     `json:"changes" gorm:"type:text"` // JSON MergeChanges
+	UndoneAt    *time.Time `json:"undone_at"`
+	UndoneBy    *uint      `json:"undone_by"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// MergeChanges is what a merge moved to the survivor, and what it removed because the
+// survivor already had it
+type MergeChanges struct {
+	Person *Person `json:"person,omitempty"` // Deleted duplicate person
+
+	MovedCredits   []MergedCredit `json:"moved_credits,omitempty"`   // As they were on the duplicate
+	DroppedCredits []MergedCredit `json:"dropped_credits,omitempty"` // Deleted, the survivor had the same credit
+
+	MovedGenreIDs   []uint `json:"moved_genre_ids,omitempty"`
+	DroppedGenreIDs []uint `json:"dropped_genre_ids,omitempty"`
+
+	MovedMovieLanguageIDs []uint `json:"moved_movie_language_ids,omitempty"`
+	KeptMovieLanguageIDs  []uint `json:"kept_movie_language_ids,omitempty"` // Left on the duplicate, the survivor has the language
+	MovedScreeningIDs     []uint `json:"moved_screening_ids,omitempty"`
+
+	MovedExternalID *string `json:"moved_external_id,omitempty"` // Given to a survivor that had none
+
+	MovedAssetIDs   []uint `json:"moved_asset_ids,omitempty"`
+	DemotedAssetIDs []uint `json:"demoted_asset_ids,omitempty"` // Were primary, the survivor has its own
+
+	MovedCertificationIDs []uint `json:"moved_certification_ids,omitempty"`
+	KeptCertificationIDs  []uint `json:"kept_certification_ids,omitempty"` // Left on the duplicate, the survivor is certified in that system
+	MovedAdvisoryIDs      []uint `json:"moved_advisory_ids,omitempty"`
+}
+
+// MergedCredit is a movie_cast row as stored in a merge record
+type MergedCredit struct {
+	MovieID       uint       `json:"movie_id"`
+	PersonID      uint       `json:"person_id"`
+	Role          CreditRole `json:"role"`
+	CharacterName string     `json:"character_name,omitempty"`
+	BillingOrder  int        `json:"billing_order"`
+}
+
+func NewMergedCredit(credit MovieCast) MergedCredit {
+	return MergedCredit{
+		MovieID:       credit.MovieID,
+		PersonID:      credit.PersonID,
+		Role:          credit.Role,
+		CharacterName: credit.CharacterName,
+		BillingOrder:  credit.BillingOrder,
+	}
+}
+
+func (c MergedCredit) MovieCast() MovieCast {
+	return MovieCast{
+		MovieID:       c.MovieID,
+		PersonID:      c.PersonID,
+		Role:          c.Role,
+		CharacterName: c.CharacterName,
+		BillingOrder:  c.BillingOrder,
+	}
+}
@@ -15,10 +15,55 @@ const (
 	RatingS  MovieRating = "S"
 )
 
+var MovieRatings = []MovieRating{RatingU, RatingUA, RatingA, RatingS}
+
+// MovieStatus is where a movie is in its theatrical run
+type MovieStatus string
+
+const (
+	MovieAnnounced      MovieStatus = "announced"
+	MovieComingSoon     MovieStatus = "coming_soon"
+	MovieAdvanceBooking MovieStatus = "advance_booking" // Bookable ahead of release
+	MovieNowShowing     MovieStatus = "now_showing"
+	MovieEnded          MovieStatus = "ended"
+)
+
+var MovieStatuses = []MovieStatus{MovieAnnounced, MovieComingSoon, MovieAdvanceBooking, MovieNowShowing, MovieEnded}
+
 type Genre struct {
 	ID     uint    `json:"id" gorm:"primaryKey"`
 	Name   string  `json:"name" gorm:"unique;not null"`
 	Movies []Movie `json:"movies" gorm:"many2many:movie_genres;"`
+
+	GenreLanguages []GenreLanguage `json:"genre_languages,omitempty"`
+	Language       string          `json:"language,omitempty" gorm:"-"` // Language Name was served in, set per request
+}
+
+// GenreLanguage is a genre's name in one language
+type GenreLanguage struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	GenreID    uint      `json:"genre_id" gorm:"not null;uniqueIndex:idx_genre_language"`
+	LanguageID uint      `json:"language_id" gorm:"not null;uniqueIndex:idx_genre_language"`
+	Name       string    `json:"name" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Language Language `json:"language,omitempty"`
+}
+
+// RatingDescription translates a default country certification in one language, e.g.
+// "U/A" as "Parental guidance for children under 12". Where a rating has none, the
+// certification's own Name and Description are served.
+type RatingDescription struct {
+	ID          uint        `json:"id" gorm:"primarykey"`
+	Rating      MovieRating `json:"rating" gorm:"not null;uniqueIndex:idx_rating_language"`
+	LanguageID  uint        `json:"language_id" gorm:"not null;uniqueIndex:idx_rating_language"`
+	Label       string      `json:"label" gorm:"not null"`
+	Description string      `json:"description"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+
+	Language Language `json:"language,omitempty"`
 }
 
 type Person struct {
@@ -28,13 +73,35 @@ type Person struct {
 	Movies []Movie `json:"movies" gorm:"many2many:movie_cast;"`
 }
 
+// MovieCast is the movie_cast join table behind Movie.Cast, one row per person and role
 type MovieCast struct {
-	MovieID       uint   `gorm:"primaryKey"`
-	PersonID      uint   `gorm:"primaryKey"`
-	Role          string // "Actor", "Director", "Producer"
-	CharacterName string // For actors
-	Movie         Movie
-	Person        Person
+	MovieID       uint       `json:"-" gorm:"primaryKey"`
+	PersonID      uint       `json:"person_id" gorm:"primaryKey"`
+	Role          CreditRole `json:"role" gorm:"primaryKey;default:'actor'"`
+	CharacterName string     `json:"character_name,omitempty"` // For actors
+	BillingOrder  int        `json:"billing_order" gorm:"default:0"`
+	Movie         Movie      `json:"-"`
+	Person        Person     `json:"person"`
+}
+
+type CreditRole string
+
+const (
+	RoleActor           CreditRole = "actor"
+	RoleDirector        CreditRole = "director"
+	RoleWriter          CreditRole = "writer"
+	RoleMusicDirector   CreditRole = "music_director"
+	RoleProducer        CreditRole = "producer"
+	RoleCinematographer CreditRole = "cinematographer"
+	RoleEditor          CreditRole = "editor"
+)
+
+var CreditRoles = []CreditRole{RoleActor, RoleDirector, RoleWriter, RoleMusicDirector, RoleProducer, RoleCinematographer, RoleEditor}
+
+// MovieCredits is a movie's cast in billing order and its crew grouped by role
+type MovieCredits struct {
+	Cast []MovieCast                `json:"cast"`
+	Crew map[CreditRole][]MovieCast `json:"crew"`
 }
 
 type Language struct {
@@ -60,6 +127,36 @@ type Movie struct {
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// Lifecycle. The status job moves movies along using ComingSoonAt, BookingOpensAt,
+	// ReleaseDate and the last screening, unless an admin has locked the status.
+	Status          MovieStatus `json:"status" gorm:"default:'announced';index"`
+	StatusLocked    bool        `json:"status_locked" gorm:"default:false"`
+	StatusChangedAt *time.Time  `json:"status_changed_at"`
+	ComingSoonAt    *time.Time  `json:"coming_soon_at"`
+	BookingOpensAt  *time.Time  `json:"booking_opens_at"`
+	EndedAt         *time.Time  `json:"ended_at"`
+
+	// ID in the distributor's or upstream catalog, used to match movies on re-import
+	ExternalID *string `json:"external_id,omitempty" gorm:"uniqueIndex"`
+
+	// References and origin
+	IMDbID             string    `json:"imdb_id,omitempty" gorm:"index"` // e.g. tt15354916
+	TMDBID             *int      `json:"tmdb_id,omitempty" gorm:"index"`
+	OriginalLanguageID *uint     `json:"original_language_id,omitempty"`
+	OriginalLanguage   *Language `json:"original_language,omitempty"`
+	CountryOfOrigin    string    `json:"country_of_origin,omitempty" gorm:"size:2"` // ISO 3166-1 alpha-2
+
+	// Set per request by localization, not stored
+	Title    string `json:"title,omitempty" gorm:"-"`
+	Language string `json:"language,omitempty" gorm:"-"` // Language Title and Description were served in
+
+	RatingLabel       string `json:"rating_label,omitempty" gorm:"-"`
+	RatingDescription string `json:"rating_description,omitempty" gorm:"-"`
+
+	// Primary images with signed URLs. When a poster is uploaded PosterURL points at it.
+	Poster   *MediaAsset `json:"poster,omitempty" gorm:"-"`
+	Backdrop *MediaAsset `json:"backdrop,omitempty" gorm:"-"`
+
 	// Proper relationships
 	Genres     []Genre     `gorm:"many2many:movie_genres;" json:"genres,omitempty"`
 	Cast       []Person    `gorm:"many2many:movie_cast;" json:"cast,omitempty"`
@@ -67,6 +164,19 @@ type Movie struct {
 
 	MovieLanguages     []MovieLanguage `json:"movie_languages,omitempty"`
 	AvailableLanguages []Language      `json:"available_languages,omitempty" gorm:"many2many:movie_languages;"`
+
+	Assets []MediaAsset `json:"assets,omitempty"`
+
+	// Regional classification. Rating is one of the default country's certification codes
+	// and is used there until a certification is recorded; recording one updates it.
+	Certifications []MovieCertification `json:"certifications,omitempty"`
+	Advisories     []ContentAdvisory    `json:"advisories,omitempty"`
+
+	// Every credit with its role and billing order. ArrangeCredits groups them into
+	// Credits for responses; Trailers is also set per request.
+	MovieCasts []MovieCast   `json:"-"`
+	Credits    *MovieCredits `json:"credits,omitempty" gorm:"-"`
+	Trailers   []MediaAsset  `json:"trailers,omitempty" gorm:"-"`
 }
 
 type MovieLanguage struct {
@@ -81,6 +191,11 @@ type MovieLanguage struct {
 	AudioFormat    string `json:"audio_format"`                       // "Dolby Atmos", "Stereo"
 	SubtitleFormat string `json:"subtitle_format"`                    // "SRT", "VTT"
 
+	// Uploaded subtitle file, kept in storage under SubtitleKey
+	SubtitleKey        string     `json:"-"`
+	SubtitleCueCount   int        `json:"subtitle_cue_count"`
+	SubtitleUploadedAt *time.Time `json:"subtitle_uploaded_at"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
@@ -88,3 +203,47 @@ type MovieLanguage struct {
 	Movie    Movie    `json:"movie,omitempty"`
 	Language Language `json:"language,omitempty"`
 }
+
+// ArrangeCredits sets Credits and Cast from the preloaded MovieCasts, which should be in
+// billing order
+func (m *Movie) ArrangeCredits() {
+	credits := MovieCredits{Cast: []MovieCast{}, Crew: make(map[CreditRole][]MovieCast)}
+	var actors []Person
+	for _, credit := range m.MovieCasts {
+		if credit.Role == RoleActor {
+			credits.Cast = append(credits.Cast, credit)
+			actors = append(actors, credit.Person)
+		} else {
+			credits.Crew[credit.Role] = append(credits.Crew[credit.Role], credit)
+		}
+	}
+	m.Credits = &credits
+	m.Cast = actors
+}
+
+// StatusAt works out the status from the movie's dates. lastShowEndsAt is the end of the
+// last scheduled screening, nil when none are scheduled.
+func (m Movie) StatusAt(now time.Time, lastShowEndsAt *time.Time) MovieStatus {
+	switch {
+	case !m.ReleaseDate.After(now):
+		if lastShowEndsAt != nil && lastShowEndsAt.Before(now) {
+			return MovieEnded
+		}
+		return MovieNowShowing
+	case m.BookingOpensAt != nil && !m.BookingOpensAt.After(now):
+		return MovieAdvanceBooking
+	case m.ComingSoonAt != nil && !m.ComingSoonAt.After(now):
+		return MovieComingSoon
+	}
+	return MovieAnnounced
+}
+
+// BookingOpen reports whether tickets can be sold for the movie. Dates are checked
+// directly so bookings open on time even between status job runs.
+func (m Movie) BookingOpen(now time.Time) bool {
+	status := m.Status
+	if !m.StatusLocked {
+		status = m.StatusAt(now, nil)
+	}
+	return status == MovieAdvanceBooking || status == MovieNowShowing
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Notification is an outbox entry for a message to a user
+type Notification struct {
+	ID      uint       `json:"id" gorm:"primarykey"`
+	UserID  uint       `json:"user_id" gorm:"not null;index"`
+	Channel string     `json:"channel" gorm:"default:'email'"`
+	Subject string     `json:"subject" gorm:"not null"`
+	Body    string     `json:"body" gorm:"type:text"`
+	Status  string     `json:"status" gorm:"default:'pending'"` // pending, sent, failed
+	SentAt  *time.Time `json:"sent_at"`
+
+	// Failed sends are retried with backoff until the configured number of attempts
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt *time.Time `json:"next_attempt_at" gorm:"index"`
+	LastError     string     `json:"last_error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type SecurityEventType string
+
+const (
+	EventLoginSucceeded         SecurityEventType = "login_succeeded"
+	EventLoginFailed            SecurityEventType = "login_failed"
+	EventLoginThrottled         SecurityEventType = "login_throttled"
+	EventAccountLocked          SecurityEventType = "account_locked"
+	EventAccountUnlocked        SecurityEventType = "account_unlocked"
+	EventIPBlocked              SecurityEventType = "ip_blocked"
+	EventPasswordChanged        SecurityEventType = "password_changed"
+	EventSessionsRevoked        SecurityEventType = "sessions_revoked"
+	EventTwoFactorEnabled       SecurityEventType = "two_factor_enabled"
+	EventTwoFactorDisabled      SecurityEventType = "two_factor_disabled"
+	EventTwoFactorFailed        SecurityEventType = "two_factor_failed"
+	EventRecoveryCodeUsed       SecurityEventType = "recovery_code_used"
+	EventPasswordResetRequested SecurityEventType = "password_reset_requested"
+	EventPasswordReset          SecurityEventType = "password_reset"
+	EventEmailVerified          SecurityEventType = "email_verified"
+	EventSSOFailed              SecurityEventType = "sso_failed"
+	EventSSOUserProvisioned     SecurityEventType = "sso_user_provisioned"
+	EventAPIKeyCreated          SecurityEventType = "api_key_created"
+	EventAPIKeyRevoked          SecurityEventType = "api_key_revoked"
+	EventImpersonationStarted   SecurityEventType = "impersonation_started"
+	EventImpersonationEnded     SecurityEventType = "impersonation_ended"
+	EventImpersonatedRequest    SecurityEventType = "impersonated_request"
+	EventAgeVerified            SecurityEventType = "age_verified"
+)
+
+// SecurityEvent is an append-only audit record of authentication activity
+type SecurityEvent struct {
+	ID        uint              `json:"id" gorm:"primarykey"`
+	Type      SecurityEventType `json:"type" gorm:"not null;index"`
+	UserID    *uint             `json:"user_id" gorm:"index"`
+	Email     string            `json:"email" gorm:"index"`
+	ActorID   *uint             `json:"actor_id"` // Admin who triggered the event, if any
+	IPAddress string            `json:"ip_address" gorm:"index"`
+	UserAgent string            `json:"user_agent"`
+	Details   string            `json:"details"`
+	CreatedAt time.Time         `json:"created_at" gorm:"index"`
+}
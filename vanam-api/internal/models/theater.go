@@ -12,6 +12,9 @@ type Theater struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// Whose certifications apply to its shows, ISO 3166-1 alpha-2. Empty means the default country.
+	Country string `json:"country" gorm:"size:2"`
+
 	// Relationships
 	Screens []Screen `json:"screens,omitempty"`
 }
@@ -83,6 +86,13 @@ type SeatPosition struct {
 	CustomNumber string  `json:"custom_number"` // For custom numbering
 }
 
+type ScreeningStatus string
+
+const (
+	ScreeningScheduled ScreeningStatus = "scheduled"
+	ScreeningCancelled ScreeningStatus = "cancelled"
+)
+
 type Screening struct {
 	ID                 uint      `json:"id" gorm:"primarykey"`
 	MovieID            uint      `json:"movie_id" gorm:"not null"`
@@ -101,9 +111,39 @@ type Screening struct {
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
 
+	// Cancellation
+	Status             ScreeningStatus `json:"status" gorm:"default:'scheduled'"`
+	CancelledAt        *time.Time      `json:"cancelled_at"`
+	CancellationReason string          `json:"cancellation_reason,omitempty"`
+
 	// Relationships
 	Movie            Movie     `json:"movie,omitempty"`
 	Screen           Screen    `json:"screen,omitempty"`
 	Language         Language  `json:"language,omitempty"`
 	SubtitleLanguage *Language `json:"subtitle_language,omitempty"`
+
+	// Computed, not stored
+	Accessibility *ScreeningAccessibility `json:"accessibility,omitempty" gorm:"-"`
+}
+
+type ScreeningAccessibility struct {
+	TotalSeats     int        `json:"total_seats"`
+	AvailableSeats int        `json:"available_seats"`
+	ReservedUntil  *time.Time `json:"reserved_until,omitempty"` // Held for accessible bookings until then
+}
+
+// StartsAt combines ShowDate and ShowTime into the moment the show begins
+func (s Screening) StartsAt() time.Time {
+	return time.Date(s.ShowDate.Year(), s.ShowDate.Month(), s.ShowDate.Day(),
+		s.ShowTime.Hour(), s.ShowTime.Minute(), s.ShowTime.Second(), 0, s.ShowDate.Location())
+}
+
+// EndsAt returns the moment the show ends, rolling over to the next day for late shows
+func (s Screening) EndsAt() time.Time {
+	end := time.Date(s.ShowDate.Year(), s.ShowDate.Month(), s.ShowDate.Day(),
+		s.EndTime.Hour(), s.EndTime.Minute(), s.EndTime.Second(), 0, s.ShowDate.Location())
+	if end.Before(s.StartsAt()) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
 }
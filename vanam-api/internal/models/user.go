@@ -9,6 +9,8 @@ import (
 type Role struct {
 	ID   uint   `json:"id" gorm:"primaryKey"`
 	Name string `json:"name" gorm:"unique;not null"`
+
+	RequireTwoFactor bool `json:"require_two_factor" gorm:"default:false"` // Members must enroll in TOTP
 }
 
 type User struct {
@@ -22,15 +24,62 @@ type User struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	MustChangePassword bool `gorm:"default:false"` // Seeded accounts must pick their own password first
+	PasswordChangedAt  *time.Time
+
+	EmailVerified   bool `gorm:"default:false"`
+	EmailVerifiedAt *time.Time
+
+	// Identity at the single sign-on provider, empty for password-only accounts
+	OIDCIssuer  string
+	OIDCSubject string `gorm:"index"`
+
+	// TOTP second factor. TOTPSecret is set on enrollment, TOTPEnabled once a code is confirmed.
+	TOTPSecret      string
+	TOTPEnabled     bool  `gorm:"default:false"`
+	TOTPLastCounter int64 // Last accepted time step, so a code can't be replayed
+	TOTPEnabledAt   *time.Time
+
+	PreferredLanguage string // Language code content is served in when the request doesn't ask for one
+
+	// Age for restricted certifications. Customers declare their date of birth, staff set
+	// AgeVerifiedAt once they have seen ID.
+	DateOfBirth   *time.Time `gorm:"type:date"`
+	AgeVerifiedAt *time.Time
+	AgeVerifiedBy *uint
+}
+
+// RecoveryCode is a single use fallback for a lost authenticator
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null;uniqueIndex"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PasswordHistory keeps hashes of a user's previous passwords so they can't be reused
+type PasswordHistory struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;index"`
+	PasswordHash string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"index"`
 }
 
 // Session model for Redis storage
 type Session struct {
-	SessionID string    `json:"session_id"`
-	UserID    uint      `json:"user_id"`
-	RoleID    uint      `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	ExpiresAt time.Time `json:"expires_at"`
-	IPAddress string    `json:"ip_address"`
-	UserAgent string    `json:"user_agent"`
+	SessionID  string    `json:"session_id"`
+	UserID     uint      `json:"user_id"`
+	RoleID     uint      `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Device     string    `json:"device"`
+
+	// Set when a support agent is acting as the user. Such sessions are time-boxed.
+	ImpersonatorID      uint   `json:"impersonator_id,omitempty"`
+	ImpersonationReason string `json:"impersonation_reason,omitempty"`
 }
@@ -0,0 +1,259 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+)
+
+var ErrNotConfigured = errors.New("single sign-on is not configured")
+
+// Provider is an OpenID Connect identity provider using the authorization code flow with PKCE
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	GroupRoles   []GroupRole // Checked in order, the first matching group wins
+	DefaultRole  string      // Role for users in no mapped group, empty to refuse them
+
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      *keySet
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// GroupRole maps an IdP group to a role name
+type GroupRole struct {
+	Group string
+	Role  string
+}
+
+// AuthRequest is the per-login state kept between the redirect and the callback
+type AuthRequest struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+var Default *Provider
+
+// Setup configures the provider from config. Discovery happens on first use, so the
+// API still starts when the IdP is unreachable.
+func Setup(cfg *config.Config) {
+	if cfg.OIDCIssuerURL == "" {
+		return
+	}
+
+	// Only admins get a session, so a mapping to any other role would create accounts
+	// that can never sign in
+	groupRoles := parseGroupRoles(cfg.OIDCRoleMapping)
+	for _, mapping := range groupRoles {
+		if mapping.Role != "admin" {
+			log.Fatalf("OIDC_ROLE_MAPPING maps %s to %s, single sign-on only supports the admin role", mapping.Group, mapping.Role)
+		}
+	}
+	if cfg.OIDCDefaultRole != "" && cfg.OIDCDefaultRole != "admin" {
+		log.Fatalf("OIDC_DEFAULT_ROLE is %s, single sign-on only supports the admin role", cfg.OIDCDefaultRole)
+	}
+
+	Default = &Provider{
+		Issuer:       strings.TrimSuffix(cfg.OIDCIssuerURL, "/"),
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       strings.Fields(cfg.OIDCScopes),
+		GroupsClaim:  cfg.OIDCGroupsClaim,
+		GroupRoles:   groupRoles,
+		DefaultRole:  cfg.OIDCDefaultRole,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+
+	log.Printf("✅ Single sign-on enabled with %s", Default.Issuer)
+}
+
+// NewAuthRequest generates the state, nonce and PKCE verifier for one login
+func NewAuthRequest() (*AuthRequest, error) {
+	state, err := randomString(24)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomString(24)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString(48)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthRequest{State: state, Nonce: nonce, CodeVerifier: verifier}, nil
+}
+
+// AuthURL is where the browser is sent to sign in
+func (p *Provider) AuthURL(ctx context.Context, req *AuthRequest) (string, error) {
+	doc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(req.CodeVerifier))
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.ClientID)
+	params.Set("redirect_uri", p.RedirectURL)
+	params.Set("scope", strings.Join(p.Scopes, " "))
+	params.Set("state", req.State)
+	params.Set("nonce", req.Nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return doc.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange trades an authorization code for tokens and returns the verified ID token claims
+func (p *Provider) Exchange(ctx context.Context, code string, req *AuthRequest) (Claims, error) {
+	doc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("code_verifier", req.CodeVerifier)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		httpReq.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return p.verifyIDToken(ctx, doc, tokens.IDToken, req.Nonce)
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var doc discoveryDocument
+	if err := p.getJSON(ctx, p.Issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", doc.Issuer, p.Issuer)
+	}
+
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// RoleFor picks the role for a user from their IdP groups
+func (p *Provider) RoleFor(groups []string) string {
+	for _, mapping := range p.GroupRoles {
+		if contains(groups, mapping.Group) {
+			return mapping.Role
+		}
+	}
+	return p.DefaultRole
+}
+
+// parseGroupRoles reads "group=role,group=role"
+func parseGroupRoles(mapping string) []GroupRole {
+	var result []GroupRole
+	for _, pair := range strings.Split(mapping, ",") {
+		group, role, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || group == "" || role == "" {
+			continue
+		}
+		result = append(result, GroupRole{Group: strings.TrimSpace(group), Role: strings.TrimSpace(role)})
+	}
+	return result
+}
+
+func randomString(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
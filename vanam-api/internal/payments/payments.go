@@ -0,0 +1,69 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+)
+
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundSucceeded RefundStatus = "succeeded"
+	RefundFailed    RefundStatus = "failed"
+)
+
+type RefundRequest struct {
+	BookingID  uint
+	BookingRef string
+	Amount     float64
+	Reason     string
+}
+
+type RefundResult struct {
+	ProviderRef string
+	Status      RefundStatus
+}
+
+// Provider is implemented by every payment gateway integration
+type Provider interface {
+	Name() string
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)
+}
+
+var Default Provider
+
+func Setup(cfg *config.Config) {
+	switch cfg.PaymentProvider {
+	case "", "manual":
+		Default = ManualProvider{}
+	default:
+		log.Fatalf("Unknown payment provider: %s", cfg.PaymentProvider)
+	}
+
+	log.Printf("✅ Payment provider: %s", Default.Name())
+}
+
+// ManualProvider records refunds for the box office to settle by hand
+type ManualProvider struct{}
+
+func (ManualProvider) Name() string {
+	return "manual"
+}
+
+func (ManualProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("refund amount cannot be negative")
+	}
+
+	log.Printf("💸 Manual refund of %.2f for booking %s: %s", req.Amount, req.BookingRef, req.Reason)
+
+	return &RefundResult{
+		ProviderRef: fmt.Sprintf("manual-%s-%d", req.BookingRef, time.Now().Unix()),
+		Status:      RefundPending,
+	}, nil
+}
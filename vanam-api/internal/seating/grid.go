@@ -0,0 +1,119 @@
+package seating
+
+import "github.com/prabalesh/vanam/vanam-api/internal/models"
+
+// Cell is one position of a screen's seat grid
+type Cell struct {
+	SeatID     uint    `json:"seat_id"`
+	Number     string  `json:"seat_number"`
+	Row        string  `json:"row"`
+	Column     int     `json:"column"`
+	Type       string  `json:"seat_type"`
+	Price      float64 `json:"price"`
+	Accessible bool    `json:"is_accessible"`
+	Taken      bool    `json:"taken"`
+	Aisle      bool    `json:"is_aisle,omitempty"` // Walkways, empty cells and anything without a seat row
+}
+
+// Grid is the seat layout of a screen with availability for one screening.
+// Row 0 is the row nearest the screen.
+type Grid struct {
+	Rows [][]Cell
+}
+
+// BuildGrid maps the layout designer's grid onto the screen's seat rows.
+// WalkwayRows and WalkwayCols are zero based grid indexes.
+func BuildGrid(layout models.SeatLayoutConfig, seats []models.Seat, taken map[uint]bool) Grid {
+	seatsByNumber := make(map[string]models.Seat, len(seats))
+	for _, seat := range seats {
+		seatsByNumber[seat.SeatNumber] = seat
+	}
+
+	walkwayRows := make(map[int]bool)
+	for _, r := range layout.WalkwayRows {
+		walkwayRows[r] = true
+	}
+	walkwayCols := make(map[int]bool)
+	for _, c := range layout.WalkwayCols {
+		walkwayCols[c] = true
+	}
+
+	grid := Grid{Rows: make([][]Cell, len(layout.Layout))}
+	for r, row := range layout.Layout {
+		grid.Rows[r] = make([]Cell, len(row))
+		for c, pos := range row {
+			seat, ok := seatsByNumber[pos.Number]
+			if !ok || pos.Type == "walkway" || pos.Type == "empty" || walkwayRows[r] || walkwayCols[c] {
+				grid.Rows[r][c] = Cell{Aisle: true}
+				continue
+			}
+
+			grid.Rows[r][c] = Cell{
+				SeatID:     seat.ID,
+				Number:     seat.SeatNumber,
+				Row:        seat.Row,
+				Column:     seat.Column,
+				Type:       seat.SeatType,
+				Price:      seat.Price,
+				Accessible: seat.IsAccessible,
+				Taken:      taken[seat.ID] || seat.Status == "blocked",
+			}
+		}
+	}
+
+	return grid
+}
+
+// Width is the widest row of the grid
+func (g Grid) Width() int {
+	width := 0
+	for _, row := range g.Rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width
+}
+
+// Find returns the grid position of a seat
+func (g Grid) Find(seatID uint) (row, col int, ok bool) {
+	for r, cells := range g.Rows {
+		for c, cell := range cells {
+			if !cell.Aisle && cell.SeatID == seatID {
+				return r, c, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// CompanionFor finds a free seat directly beside seatID without crossing an aisle,
+// preferring standard seats so accessible ones stay available
+func CompanionFor(g Grid, seatID uint, exclude map[uint]bool) (Cell, bool) {
+	r, c, ok := g.Find(seatID)
+	if !ok {
+		return Cell{}, false
+	}
+
+	var fallback *Cell
+	for _, col := range []int{c + 1, c - 1} {
+		if col < 0 || col >= len(g.Rows[r]) {
+			continue
+		}
+		cell := g.Rows[r][col]
+		if cell.Aisle || cell.Taken || exclude[cell.SeatID] {
+			continue
+		}
+		if !cell.Accessible {
+			return cell, true
+		}
+		if fallback == nil {
+			fallback = &cell
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Cell{}, false
+}
@@ -0,0 +1,144 @@
+package seating
+
+import (
+	"math"
+	"sort"
+)
+
+// Scoring weights, a perfect group scores 100
+const (
+	centreWeight = 40.0
+	rowWeight    = 40.0
+	orphanWeight = 25.0
+
+	// Fraction of the way from the screen to the back wall that gives the best view
+	idealRowFraction = 0.6
+)
+
+type Options struct {
+	Count             int
+	SeatType          string // Only suggest seats of this type when set
+	Limit             int    // Number of suggestions to return
+	ExcludeAccessible bool   // Keep accessible seats out of suggestions
+}
+
+type Suggestion struct {
+	Seats   []Cell  `json:"seats"`
+	Score   float64 `json:"score"`
+	Orphans int     `json:"orphans"` // Single free seats the group would strand
+}
+
+// Suggest finds the best groups of adjacent free seats. Groups never cross an aisle.
+func Suggest(grid Grid, opts Options) []Suggestion {
+	if opts.Count < 1 {
+		return nil
+	}
+
+	width := grid.Width()
+	var suggestions []Suggestion
+
+	for r, row := range grid.Rows {
+		for _, run := range seatRuns(row) {
+			for start := run.start; start+opts.Count <= run.end; start++ {
+				if !windowFree(row, start, opts) {
+					continue
+				}
+
+				orphans := strandedSeats(row, run, start, start+opts.Count)
+				suggestions = append(suggestions, Suggestion{
+					Seats:   append([]Cell(nil), row[start:start+opts.Count]...),
+					Score:   score(r, len(grid.Rows), start, opts.Count, width, orphans),
+					Orphans: orphans,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if opts.Limit > 0 && len(suggestions) > opts.Limit {
+		suggestions = suggestions[:opts.Limit]
+	}
+	return suggestions
+}
+
+// run is a stretch of seats between two aisles, [start, end)
+type run struct {
+	start, end int
+}
+
+func seatRuns(row []Cell) []run {
+	var runs []run
+	start := -1
+	for c, cell := range row {
+		if cell.Aisle {
+			if start >= 0 {
+				runs = append(runs, run{start, c})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = c
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, run{start, len(row)})
+	}
+	return runs
+}
+
+func windowFree(row []Cell, start int, opts Options) bool {
+	for c := start; c < start+opts.Count; c++ {
+		cell := row[c]
+		if cell.Taken || (opts.SeatType != "" && cell.Type != opts.SeatType) || (opts.ExcludeAccessible && cell.Accessible) {
+			return false
+		}
+	}
+	return true
+}
+
+// strandedSeats counts gaps of exactly one free seat left on either side of the group
+func strandedSeats(row []Cell, r run, start, end int) int {
+	orphans := 0
+
+	left := 0
+	for c := start - 1; c >= r.start && !row[c].Taken; c-- {
+		left++
+	}
+	if left == 1 {
+		orphans++
+	}
+
+	right := 0
+	for c := end; c < r.end && !row[c].Taken; c++ {
+		right++
+	}
+	if right == 1 {
+		orphans++
+	}
+
+	return orphans
+}
+
+func score(row, rows, start, count, width, orphans int) float64 {
+	// Horizontal distance of the group's centre from the screen's centre line, 0..1
+	centre := float64(start) + float64(count-1)/2
+	screenCentre := float64(width-1) / 2
+	centreOffset := 0.0
+	if width > 1 {
+		centreOffset = math.Abs(centre-screenCentre) / (float64(width) / 2)
+	}
+
+	// Distance from the ideal viewing row, 0..1
+	rowOffset := 0.0
+	if rows > 1 {
+		ideal := idealRowFraction * float64(rows-1)
+		rowOffset = math.Abs(float64(row)-ideal) / float64(rows-1)
+	}
+
+	s := 100 - centreWeight*centreOffset - rowWeight*rowOffset - orphanWeight*float64(orphans)
+	return math.Round(s*100) / 100
+}
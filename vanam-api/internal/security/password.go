@@ -0,0 +1,244 @@
+package security
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+	"github.com/prabalesh/vanam/vanam-api/internal/database"
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// bcrypt ignores everything past 72 bytes
+const passwordMaxLength = 72
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+var (
+	blocklistOnce sync.Once
+	blocklist     map[string]bool
+)
+
+// PasswordPolicy is the configured password policy, also published to clients
+type PasswordPolicy struct {
+	MinLength     int  `json:"min_length"`
+	MaxLength     int  `json:"max_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	HistorySize   int  `json:"history_size"` // Recent passwords that can't be reused, including the current one
+	MaxAgeDays    int  `json:"max_age_days"` // 0 means passwords never expire
+}
+
+// PasswordViolation is one way a password falls short of the policy
+type PasswordViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CurrentPasswordPolicy reads the policy from config
+func CurrentPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     config.App.PasswordMinLength,
+		MaxLength:     passwordMaxLength,
+		RequireUpper:  config.App.PasswordRequireUpper,
+		RequireLower:  config.App.PasswordRequireLower,
+		RequireDigit:  config.App.PasswordRequireDigit,
+		RequireSymbol: config.App.PasswordRequireSymbol,
+		HistorySize:   config.App.PasswordHistorySize,
+		MaxAgeDays:    config.App.PasswordMaxAgeDays,
+	}
+}
+
+// ValidatePassword checks a candidate against the policy and the blocklist. Personal
+// details such as the user's email and name are rejected as passwords too.
+func ValidatePassword(password string, personal ...string) []PasswordViolation {
+	policy := CurrentPasswordPolicy()
+	violations := []PasswordViolation{}
+
+	length := len([]rune(password))
+	if length < policy.MinLength {
+		violations = append(violations, PasswordViolation{"too_short", fmt.Sprintf("Password must be at least %d characters long", policy.MinLength)})
+	}
+	if len(password) > policy.MaxLength {
+		violations = append(violations, PasswordViolation{"too_long", fmt.Sprintf("Password must be at most %d bytes long", policy.MaxLength)})
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if policy.RequireUpper && !upper {
+		violations = append(violations, PasswordViolation{"missing_upper", "Password must contain an uppercase letter"})
+	}
+	if policy.RequireLower && !lower {
+		violations = append(violations, PasswordViolation{"missing_lower", "Password must contain a lowercase letter"})
+	}
+	if policy.RequireDigit && !digit {
+		violations = append(violations, PasswordViolation{"missing_digit", "Password must contain a digit"})
+	}
+	if policy.RequireSymbol && !symbol {
+		violations = append(violations, PasswordViolation{"missing_symbol", "Password must contain a symbol"})
+	}
+
+	if isCommonPassword(password) {
+		violations = append(violations, PasswordViolation{"breached", "Password is too common or has appeared in a data breach"})
+	}
+
+	if containsPersonalInfo(password, personal) {
+		violations = append(violations, PasswordViolation{"personal_info", "Password must not contain your name or email"})
+	}
+
+	return violations
+}
+
+// containsPersonalInfo looks for names and email local parts, whole or word by word
+func containsPersonalInfo(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(value)
+		if i := strings.Index(value, "@"); i >= 0 {
+			value = value[:i]
+		}
+
+		words := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, candidate := range append(words, strings.Join(words, "")) {
+			if len(candidate) >= 4 && strings.Contains(lowered, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PasswordReused reports whether password matches the user's current password or one
+// of the recent ones the policy remembers
+func PasswordReused(user models.User, password string) bool {
+	historySize := config.App.PasswordHistorySize
+	if historySize <= 0 {
+		return false
+	}
+
+	if user.Password != "" && utils.CheckPasswordHash(password, user.Password) {
+		return true
+	}
+
+	var history []models.PasswordHistory
+	database.DB.Where("user_id = ?", user.ID).Order("created_at DESC, id DESC").Limit(historySize - 1).Find(&history)
+	for _, entry := range history {
+		if utils.CheckPasswordHash(password, entry.PasswordHash) {
+			return true
+		}
+	}
+	return false
+}
+
+// RememberPassword moves a user's outgoing password hash into their history and drops
+// entries the policy no longer needs
+func RememberPassword(tx *gorm.DB, userID uint, oldHash string) error {
+	keep := config.App.PasswordHistorySize - 1
+	if keep <= 0 || oldHash == "" {
+		return tx.Where("user_id = ?", userID).Delete(&models.PasswordHistory{}).Error
+	}
+
+	if err := tx.Create(&models.PasswordHistory{UserID: userID, PasswordHash: oldHash}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("user_id = ? AND id NOT IN (?)", userID,
+		tx.Model(&models.PasswordHistory{}).Select("id").Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(keep),
+	).Delete(&models.PasswordHistory{}).Error
+}
+
+// PasswordExpiresAt returns when the user must rotate their password, or nil if never
+func PasswordExpiresAt(user models.User) *time.Time {
+	maxAge := config.App.PasswordMaxAgeDays
+	// Single sign-on accounts never use their password
+	if maxAge <= 0 || user.OIDCSubject != "" {
+		return nil
+	}
+
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+
+	expiresAt := changedAt.AddDate(0, 0, maxAge)
+	return &expiresAt
+}
+
+// PasswordExpired reports whether the user's password is past the maximum age
+func PasswordExpired(user models.User) bool {
+	expiresAt := PasswordExpiresAt(user)
+	return expiresAt != nil && time.Now().After(*expiresAt)
+}
+
+// isCommonPassword looks a password up in the blocklist, also without the digits and
+// symbols people tend to tack on the end
+func isCommonPassword(password string) bool {
+	blocklistOnce.Do(loadBlocklist)
+
+	lowered := strings.ToLower(password)
+	if blocklist[lowered] {
+		return true
+	}
+
+	stripped := strings.TrimRightFunc(lowered, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return len(stripped) >= 4 && blocklist[stripped]
+}
+
+func loadBlocklist() {
+	blocklist = make(map[string]bool)
+	readBlocklist(strings.NewReader(commonPasswords))
+
+	path := config.App.PasswordBlocklistFile
+	if path == "" {
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("❌ Failed to open password blocklist %s: %v", path, err)
+		return
+	}
+	defer file.Close()
+
+	readBlocklist(file)
+	log.Printf("✅ Password blocklist loaded with %d entries", len(blocklist))
+}
+
+func readBlocklist(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist[strings.ToLower(line)] = true
+	}
+}
@@ -0,0 +1,91 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/utils"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+)
+
+// A challenge is the partial session between a correct password and a verified second factor
+const (
+	ChallengeTTL         = 5 * time.Minute
+	maxChallengeAttempts = 5
+)
+
+var ErrChallengeExhausted = errors.New("too many attempts for this challenge")
+
+type Challenge struct {
+	Token     string    `json:"token"`
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+}
+
+func challengeKey(token string) string {
+	return "mfa_challenge:" + token
+}
+
+// CreateChallenge starts a second factor challenge for a user
+func CreateChallenge(userID uint, email, ip, userAgent string) (*Challenge, error) {
+	challenge := Challenge{
+		Token:     utils.GenerateSecureToken(),
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: time.Now().Add(ChallengeTTL),
+		IPAddress: ip,
+		UserAgent: userAgent,
+	}
+
+	if err := saveChallenge(&challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// GetChallenge loads a live challenge
+func GetChallenge(token string) (*Challenge, error) {
+	data, err := redis.Client.Get(redis.Ctx, challengeKey(token)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge Challenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// FailChallenge counts a wrong code, dropping the challenge once it runs out of attempts
+func FailChallenge(challenge *Challenge) error {
+	challenge.Attempts++
+	if challenge.Attempts >= maxChallengeAttempts {
+		DeleteChallenge(challenge.Token)
+		return ErrChallengeExhausted
+	}
+	return saveChallenge(challenge)
+}
+
+// DeleteChallenge ends a challenge
+func DeleteChallenge(token string) {
+	redis.Client.Del(redis.Ctx, challengeKey(token))
+}
+
+func saveChallenge(challenge *Challenge) error {
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return ErrSessionExpired
+	}
+
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return redis.Client.Set(redis.Ctx, challengeKey(challenge.Token), data, ttl).Err()
+}
@@ -0,0 +1,249 @@
+package sessions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/models"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+)
+
+// indexTTL keeps a user's index alive at least as long as their longest session
+const indexTTL = 24 * time.Hour
+
+var ErrSessionExpired = errors.New("session expired")
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// userKey is the set of session IDs belonging to a user
+func userKey(userID uint) string {
+	return fmt.Sprintf("user_sessions:%d", userID)
+}
+
+// Handle is the public identifier of a session. The session ID itself is the
+// bearer token, so it is never shown in listings.
+func Handle(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Create stores a new session and adds it to the user's index
+func Create(session *models.Session, ttl time.Duration) error {
+	session.LastSeenAt = session.CreatedAt
+	session.ExpiresAt = session.CreatedAt.Add(ttl)
+	if session.Device == "" {
+		session.Device = DeviceFromUserAgent(session.UserAgent)
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	pipe := redis.Client.TxPipeline()
+	pipe.Set(redis.Ctx, sessionKey(session.SessionID), sessionJSON, ttl)
+	pipe.SAdd(redis.Ctx, userKey(session.UserID), session.SessionID)
+	pipe.Expire(redis.Ctx, userKey(session.UserID), indexTTL)
+	_, err = pipe.Exec(redis.Ctx)
+	return err
+}
+
+// Get loads a live session
+func Get(sessionID string) (*models.Session, error) {
+	sessionData, err := redis.Client.Get(redis.Ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var session models.Session
+	if err := json.Unmarshal([]byte(sessionData), &session); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		Delete(&session)
+		return nil, ErrSessionExpired
+	}
+
+	return &session, nil
+}
+
+// Touch records activity on a session and slides its expiry forward.
+// Impersonation sessions keep their original expiry.
+func Touch(session *models.Session, ttl time.Duration, ip string) error {
+	session.LastSeenAt = time.Now()
+	if session.ImpersonatorID != 0 {
+		ttl = session.ExpiresAt.Sub(session.LastSeenAt)
+		if ttl <= 0 {
+			Delete(session)
+			return ErrSessionExpired
+		}
+	} else {
+		session.ExpiresAt = session.LastSeenAt.Add(ttl)
+	}
+	if ip != "" {
+		session.IPAddress = ip
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	pipe := redis.Client.TxPipeline()
+	pipe.Set(redis.Ctx, sessionKey(session.SessionID), sessionJSON, ttl)
+	pipe.Expire(redis.Ctx, userKey(session.UserID), indexTTL)
+	_, err = pipe.Exec(redis.Ctx)
+	return err
+}
+
+// Delete removes a session and its index entry
+func Delete(session *models.Session) error {
+	pipe := redis.Client.TxPipeline()
+	pipe.Del(redis.Ctx, sessionKey(session.SessionID))
+	pipe.SRem(redis.Ctx, userKey(session.UserID), session.SessionID)
+	_, err := pipe.Exec(redis.Ctx)
+	return err
+}
+
+// DeleteByID removes a session when only its ID is known
+func DeleteByID(sessionID string) error {
+	session, err := Get(sessionID)
+	if err != nil {
+		// Already gone or unreadable, make sure the key is removed anyway
+		return redis.Client.Del(redis.Ctx, sessionKey(sessionID)).Err()
+	}
+	return Delete(session)
+}
+
+// ListForUser returns a user's live sessions, most recently used first.
+// Index entries whose session has expired are pruned along the way.
+func ListForUser(userID uint) ([]models.Session, error) {
+	ids, err := redis.Client.SMembers(redis.Ctx, userKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []models.Session{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = sessionKey(id)
+	}
+
+	values, err := redis.Client.MGet(redis.Ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []models.Session{}
+	var stale []interface{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			stale = append(stale, ids[i])
+			continue
+		}
+
+		var session models.Session
+		if json.Unmarshal([]byte(data), &session) != nil || time.Now().After(session.ExpiresAt) {
+			stale = append(stale, ids[i])
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(stale) > 0 {
+		redis.Client.SRem(redis.Ctx, userKey(userID), stale...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeAll logs a user out everywhere except the sessions in keep and
+// returns how many sessions were removed
+func RevokeAll(userID uint, keep ...string) (int, error) {
+	ids, err := redis.Client.SMembers(redis.Ctx, userKey(userID)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, id := range keep {
+		kept[id] = true
+	}
+
+	var keys []string
+	var members []interface{}
+	for _, id := range ids {
+		if kept[id] {
+			continue
+		}
+		keys = append(keys, sessionKey(id))
+		members = append(members, id)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	pipe := redis.Client.TxPipeline()
+	deleted := pipe.Del(redis.Ctx, keys...)
+	pipe.SRem(redis.Ctx, userKey(userID), members...)
+	if _, err := pipe.Exec(redis.Ctx); err != nil {
+		return 0, err
+	}
+
+	return int(deleted.Val()), nil
+}
+
+// DeviceFromUserAgent gives a short, human readable device label
+func DeviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	case strings.Contains(ua, "postman"):
+		browser = "Postman"
+	}
+
+	os := "Unknown OS"
+	switch {
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		os = "iOS"
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "mac os"):
+		os = "macOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	return browser + " on " + os
+}
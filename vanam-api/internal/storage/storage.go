@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/prabalesh/vanam/vanam-api/internal/config"
+)
+
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage is implemented by every file storage backend. Keys are slash separated
+// relative paths such as "subtitles/12/ta.srt".
+type Storage interface {
+	Name() string
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var Default Storage
+
+func Setup(cfg *config.Config) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		Default = LocalStorage{Dir: cfg.StorageDir}
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			log.Fatal("S3 storage requires S3_ENDPOINT and S3_BUCKET")
+		}
+		Default = S3Storage{
+			Endpoint:  strings.TrimRight(cfg.S3Endpoint, "/"),
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}
+	default:
+		log.Fatalf("Unknown storage driver: %s", cfg.StorageDriver)
+	}
+
+	log.Printf("✅ Storage driver: %s", Default.Name())
+}
+
+// cleanKey rejects keys that could escape the storage root
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,165 @@
+package subtitles
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hours are optional in WebVTT, and SRT files in the wild often use '.' instead of ','
+var timingLine = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})(.*)$`)
+
+// WebVTT-only markup that SRT players would show as literal text
+var vttOnlyTags = regexp.MustCompile(`</?(?:c|v|lang|ruby|rt)(?:[.\s][^>]*)?>|<\d{2}:[\d:.]+>`)
+
+type block struct {
+	line  int
+	lines []string
+}
+
+// splitBlocks splits normalized text into blank line separated blocks
+func splitBlocks(text string) []block {
+	var blocks []block
+	var current *block
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, block{line: i + 1})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+	return blocks
+}
+
+func parseSRT(text string) ([]Cue, []Problem) {
+	var cues []Cue
+	var problems []Problem
+
+	for _, b := range splitBlocks(text) {
+		lines := b.lines
+		line := b.line
+
+		id := ""
+		if !strings.Contains(lines[0], "-->") {
+			id = strings.TrimSpace(lines[0])
+			if n, err := strconv.Atoi(id); err != nil {
+				problems = append(problems, Problem{Severity: SeverityWarning, Code: "bad_index", Line: line,
+					Message: fmt.Sprintf("Expected a cue number, found %q", id)})
+			} else if n != len(cues)+1 {
+				problems = append(problems, Problem{Severity: SeverityWarning, Code: "index_sequence", Line: line,
+					Message: fmt.Sprintf("Cue number %d is out of sequence, expected %d", n, len(cues)+1)})
+			}
+			lines = lines[1:]
+			line++
+		} else {
+			problems = append(problems, Problem{Severity: SeverityWarning, Code: "missing_index", Line: line, Message: "Cue has no number"})
+		}
+
+		cue, problem := parseCue(lines, line, id)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		cue.Settings = "" // SRT has no cue settings, anything after the timing is noise
+		cues = append(cues, cue)
+	}
+
+	return cues, problems
+}
+
+func parseVTT(text string) ([]Cue, []Problem) {
+	blocks := splitBlocks(text)
+	if len(blocks) == 0 || !isVTTHeader(blocks[0].lines[0]) {
+		return nil, []Problem{{Severity: SeverityError, Code: "missing_header", Line: 1, Message: "WebVTT files must start with WEBVTT"}}
+	}
+
+	var cues []Cue
+	var problems []Problem
+	for _, b := range blocks[1:] {
+		first := strings.TrimSpace(b.lines[0])
+		if first == "NOTE" || strings.HasPrefix(first, "NOTE ") || first == "STYLE" || first == "REGION" {
+			continue
+		}
+
+		lines := b.lines
+		line := b.line
+		id := ""
+		if !strings.Contains(lines[0], "-->") {
+			id = first
+			lines = lines[1:]
+			line++
+		}
+
+		cue, problem := parseCue(lines, line, id)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		cues = append(cues, cue)
+	}
+
+	return cues, problems
+}
+
+func isVTTHeader(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
+
+// parseCue reads a timing line followed by the cue text
+func parseCue(lines []string, line int, id string) (Cue, *Problem) {
+	if len(lines) == 0 {
+		return Cue{}, &Problem{Severity: SeverityError, Code: "missing_timing", Line: line, Message: "Cue has no timing line"}
+	}
+
+	match := timingLine.FindStringSubmatch(lines[0])
+	if match == nil {
+		return Cue{}, &Problem{Severity: SeverityError, Code: "bad_timing", Line: line,
+			Message: fmt.Sprintf("Expected a timing line like 00:00:01,000 --> 00:00:02,500, found %q", lines[0])}
+	}
+
+	start, err := parseTimestamp(match[1])
+	if err == nil {
+		var end time.Duration
+		if end, err = parseTimestamp(match[2]); err == nil {
+			return Cue{
+				ID:       id,
+				Start:    start,
+				End:      end,
+				Settings: strings.TrimSpace(match[3]),
+				Text:     strings.Join(lines[1:], "\n"),
+				Line:     line,
+			}, nil
+		}
+	}
+	return Cue{}, &Problem{Severity: SeverityError, Code: "bad_timing", Line: line, Message: err.Error()}
+}
+
+// parseTimestamp reads [hh:]mm:ss.ttt or [hh:]mm:ss,ttt
+func parseTimestamp(s string) (time.Duration, error) {
+	s = strings.Replace(s, ",", ".", 1)
+	clock, fraction, _ := strings.Cut(s, ".")
+
+	parts := strings.Split(clock, ":")
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+
+	hours, _ := strconv.Atoi(parts[0])
+	minutes, _ := strconv.Atoi(parts[1])
+	seconds, _ := strconv.Atoi(parts[2])
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	// "1.5" means 500ms, so pad the fraction out to milliseconds
+	ms, _ := strconv.Atoi((fraction + "00")[:3])
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
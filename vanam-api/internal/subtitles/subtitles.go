@@ -0,0 +1,202 @@
+package subtitles
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Format string
+
+const (
+	FormatSRT Format = "srt"
+	FormatVTT Format = "vtt"
+)
+
+// ParseFormat accepts "srt"/"vtt" in any case, as stored in MovieLanguage.SubtitleFormat
+func ParseFormat(s string) (Format, bool) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case FormatSRT:
+		return FormatSRT, true
+	case FormatVTT, "webvtt":
+		return FormatVTT, true
+	}
+	return "", false
+}
+
+// ContentType is the MIME type files of the format are served with
+func (f Format) ContentType() string {
+	if f == FormatVTT {
+		return "text/vtt; charset=utf-8"
+	}
+	return "application/x-subrip; charset=utf-8"
+}
+
+// Cue is one timed caption
+type Cue struct {
+	ID       string // SRT sequence number or optional WebVTT identifier
+	Start    time.Duration
+	End      time.Duration
+	Settings string // WebVTT cue settings such as "align:start", dropped in SRT
+	Text     string
+	Line     int // Line of the timing line in the source file
+}
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is something wrong with a subtitle file. Errors make the file unusable,
+// warnings are worth a look but don't block it.
+type Problem struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// HasErrors reports whether any problem is an error
+func HasErrors(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEncoding makes sure the file is UTF-8 text and returns it with the byte order mark
+// removed and line endings normalized. Files saved as UTF-16 or in a legacy code page
+// are common from older subtitle tools and are reported rather than guessed at.
+func CheckEncoding(data []byte) ([]byte, []Problem) {
+	if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) || bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+		return nil, []Problem{{Severity: SeverityError, Code: "utf16", Message: "File is UTF-16 encoded, save it as UTF-8"}}
+	}
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, []Problem{{Severity: SeverityError, Code: "binary", Message: "File contains NUL bytes, it is binary or UTF-16 without a byte order mark"}}
+	}
+
+	if !utf8.Valid(data) {
+		line := 1 + bytes.Count(data[:firstInvalidUTF8(data)], []byte("\n"))
+		return nil, []Problem{{Severity: SeverityError, Code: "invalid_utf8", Line: line,
+			Message: "File is not valid UTF-8, it was probably saved in a legacy encoding such as Windows-1252"}}
+	}
+
+	var problems []Problem
+	if bytes.ContainsRune(data, utf8.RuneError) {
+		problems = append(problems, Problem{Severity: SeverityWarning, Code: "replacement_character",
+			Message: "File contains U+FFFD replacement characters, text may have been damaged by an earlier conversion"})
+	}
+
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+	return data, problems
+}
+
+func firstInvalidUTF8(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return len(data)
+}
+
+// DetectFormat guesses the format from the content, which is more reliable than the
+// file extension
+func DetectFormat(data []byte) Format {
+	if bytes.HasPrefix(bytes.TrimLeft(data, " \t\n"), []byte("WEBVTT")) {
+		return FormatVTT
+	}
+	return FormatSRT
+}
+
+// Parse reads a UTF-8 subtitle file as returned by CheckEncoding
+func Parse(data []byte, format Format) ([]Cue, []Problem) {
+	if format == FormatVTT {
+		return parseVTT(string(data))
+	}
+	return parseSRT(string(data))
+}
+
+// Validate checks cue timing against the running time of the movie
+func Validate(cues []Cue, runtime time.Duration) []Problem {
+	var problems []Problem
+	if len(cues) == 0 {
+		return []Problem{{Severity: SeverityError, Code: "empty", Message: "File has no cues"}}
+	}
+
+	for i, cue := range cues {
+		if cue.End <= cue.Start {
+			problems = append(problems, Problem{Severity: SeverityError, Code: "end_before_start", Line: cue.Line,
+				Message: fmt.Sprintf("Cue ends at %s, not after it starts at %s", formatTimestamp(cue.End, "."), formatTimestamp(cue.Start, "."))})
+		}
+		if runtime > 0 && cue.Start >= runtime {
+			problems = append(problems, Problem{Severity: SeverityError, Code: "after_movie_end", Line: cue.Line,
+				Message: fmt.Sprintf("Cue starts at %s, after the movie ends at %s", formatTimestamp(cue.Start, "."), formatTimestamp(runtime, "."))})
+		} else if runtime > 0 && cue.End > runtime {
+			problems = append(problems, Problem{Severity: SeverityWarning, Code: "past_movie_end", Line: cue.Line,
+				Message: fmt.Sprintf("Cue runs until %s, past the end of the movie", formatTimestamp(cue.End, "."))})
+		}
+		if strings.TrimSpace(cue.Text) == "" {
+			problems = append(problems, Problem{Severity: SeverityWarning, Code: "empty_cue", Line: cue.Line, Message: "Cue has no text"})
+		}
+
+		if i == 0 {
+			continue
+		}
+		previous := cues[i-1]
+		if cue.Start < previous.Start {
+			problems = append(problems, Problem{Severity: SeverityError, Code: "out_of_order", Line: cue.Line,
+				Message: "Cue starts before the previous cue, cues must be in time order"})
+		} else if cue.Start < previous.End {
+			problems = append(problems, Problem{Severity: SeverityWarning, Code: "overlap", Line: cue.Line,
+				Message: "Cue overlaps the previous cue, players may show both at once"})
+		}
+	}
+
+	return problems
+}
+
+// Encode writes cues in the given format
+func Encode(cues []Cue, format Format) []byte {
+	var b strings.Builder
+	if format == FormatVTT {
+		b.WriteString("WEBVTT\n\n")
+	}
+
+	for i, cue := range cues {
+		text := cue.Text
+		if format == FormatVTT {
+			if cue.ID != "" {
+				b.WriteString(cue.ID + "\n")
+			}
+			b.WriteString(formatTimestamp(cue.Start, ".") + " --> " + formatTimestamp(cue.End, "."))
+			if cue.Settings != "" {
+				b.WriteString(" " + cue.Settings)
+			}
+		} else {
+			fmt.Fprintf(&b, "%d\n", i+1)
+			b.WriteString(formatTimestamp(cue.Start, ",") + " --> " + formatTimestamp(cue.End, ","))
+			text = vttOnlyTags.ReplaceAllString(text, "")
+		}
+		b.WriteString("\n" + text + "\n\n")
+	}
+
+	return []byte(b.String())
+}
+
+func formatTimestamp(d time.Duration, fractionSeparator string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, fractionSeparator, ms%1000)
+}
@@ -0,0 +1,238 @@
+package subtitles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+func codes(problems []Problem) []string {
+	var codes []string
+	for _, problem := range problems {
+		codes = append(codes, problem.Severity+":"+problem.Code)
+	}
+	return codes
+}
+
+func TestCheckEncoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		codes []string
+	}{
+		{name: "plain", input: "1\n00:00:01,000 --> 00:00:02,000\nHi\n", want: "1\n00:00:01,000 --> 00:00:02,000\nHi\n"},
+		{name: "byte order mark", input: "\ufeffWEBVTT\n", want: "WEBVTT\n"},
+		{name: "windows and old mac line endings", input: "a\r\nb\rc", want: "a\nb\nc"},
+		{name: "UTF-16", input: "\xff\xfe1\x00", codes: []string{"error:utf16"}},
+		{name: "NUL bytes", input: "1\x00\n", codes: []string{"error:binary"}},
+		{name: "Windows-1252", input: "1\nCaf\xe9\n", codes: []string{"error:invalid_utf8"}},
+		{name: "replacement character", input: "Caf\ufffd", want: "Caf\ufffd", codes: []string{"warning:replacement_character"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, problems := CheckEncoding([]byte(tt.input))
+			if string(data) != tt.want {
+				t.Errorf("data = %q, want %q", data, tt.want)
+			}
+			if got := codes(problems); !reflect.DeepEqual(got, tt.codes) {
+				t.Errorf("problems = %v, want %v", got, tt.codes)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		want   []Cue
+		codes  []string
+	}{
+		{
+			name:   "SRT",
+			format: FormatSRT,
+			input:  "1\n00:00:01,000 --> 00:00:02,500\nHello\nthere\n\n2\n01:02:03,040 --> 01:02:04,000\nBye\n",
+			want: []Cue{
+				{ID: "1", Start: ms(1000), End: ms(2500), Text: "Hello\nthere", Line: 2},
+				{ID: "2", Start: time.Hour + 2*time.Minute + ms(3040), End: time.Hour + 2*time.Minute + ms(4000), Text: "Bye", Line: 7},
+			},
+		},
+		{
+			name:   "SRT with dots, short fractions and extra blank lines",
+			format: FormatSRT,
+			input:  "\n\n1\n00:00:01.5 --> 00:00:02.25 X1:10\nHello\n\n\n\n2\n00:03.000 --> 00:04.000\nBye\n",
+			want: []Cue{
+				{ID: "1", Start: ms(1500), End: ms(2250), Text: "Hello", Line: 4},
+				{ID: "2", Start: ms(3000), End: ms(4000), Text: "Bye", Line: 10},
+			},
+		},
+		{
+			name:   "SRT numbering problems",
+			format: FormatSRT,
+			input:  "00:00:01,000 --> 00:00:02,000\nNo number\n\n5\n00:00:03,000 --> 00:00:04,000\nSkipped\n\nthree\n00:00:05,000 --> 00:00:06,000\nWord\n",
+			want: []Cue{
+				{Start: ms(1000), End: ms(2000), Text: "No number", Line: 1},
+				{ID: "5", Start: ms(3000), End: ms(4000), Text: "Skipped", Line: 5},
+				{ID: "three", Start: ms(5000), End: ms(6000), Text: "Word", Line: 9},
+			},
+			codes: []string{"warning:missing_index", "warning:index_sequence", "warning:bad_index"},
+		},
+		{
+			name:   "SRT bad timestamps",
+			format: FormatSRT,
+			input:  "1\n00:61:00,000 --> 00:62:00,000\nMinutes\n\n2\n00:00:01 --> 00:00:02\nNo fraction\n\n3\n\n4\n00:00:05,000 --> 00:00:06,000\nGood\n",
+			want: []Cue{
+				{ID: "4", Start: ms(5000), End: ms(6000), Text: "Good", Line: 12},
+			},
+			// Dropped cues aren't counted, so the numbers after them look out of sequence
+			codes: []string{
+				"error:bad_timing",
+				"warning:index_sequence", "error:bad_timing",
+				"warning:index_sequence", "error:missing_timing",
+				"warning:index_sequence",
+			},
+		},
+		{
+			name:   "WebVTT",
+			format: FormatVTT,
+			input:  "WEBVTT - Leo\n\nNOTE written by hand\n\nSTYLE\n::cue { color: yellow }\n\nintro\n00:01.000 --> 00:02.000 align:start line:0\n<v Leo>Hello</v>\n\n00:00:03.000 --> 00:00:04.000\nBye\n",
+			want: []Cue{
+				{ID: "intro", Start: ms(1000), End: ms(2000), Settings: "align:start line:0", Text: "<v Leo>Hello</v>", Line: 9},
+				{Start: ms(3000), End: ms(4000), Text: "Bye", Line: 12},
+			},
+		},
+		{
+			name:   "WebVTT without a header",
+			format: FormatVTT,
+			input:  "00:01.000 --> 00:02.000\nHello\n",
+			codes:  []string{"error:missing_header"},
+		},
+		{
+			name:   "WebVTT bad timestamp",
+			format: FormatVTT,
+			input:  "WEBVTT\n\n00:01.000 --> 00:02.000\nHello\n\n00:00:03,000 -> 00:00:04,000\nArrow\n",
+			want:   []Cue{{Start: ms(1000), End: ms(2000), Text: "Hello", Line: 3}},
+			codes:  []string{"error:bad_timing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues, problems := Parse([]byte(tt.input), tt.format)
+			if !reflect.DeepEqual(cues, tt.want) {
+				t.Errorf("cues = %+v, want %+v", cues, tt.want)
+			}
+			if got := codes(problems); !reflect.DeepEqual(got, tt.codes) {
+				t.Errorf("problems = %v, want %v", got, tt.codes)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cues    []Cue
+		runtime time.Duration
+		codes   []string
+	}{
+		{name: "empty file", codes: []string{"error:empty"}},
+		{
+			name:    "valid",
+			cues:    []Cue{{Start: ms(1000), End: ms(2000), Text: "a"}, {Start: ms(2000), End: ms(3000), Text: "b"}},
+			runtime: time.Minute,
+		},
+		{
+			name:  "ends before it starts",
+			cues:  []Cue{{Start: ms(2000), End: ms(2000), Text: "a"}},
+			codes: []string{"error:end_before_start"},
+		},
+		{
+			name:    "past the movie",
+			cues:    []Cue{{Start: ms(59000), End: ms(61000), Text: "a"}, {Start: ms(61000), End: ms(62000), Text: "b"}},
+			runtime: time.Minute,
+			codes:   []string{"warning:past_movie_end", "error:after_movie_end"},
+		},
+		{
+			name: "no runtime skips the movie end checks",
+			cues: []Cue{{Start: 10 * time.Hour, End: 11 * time.Hour, Text: "a"}},
+		},
+		{
+			name:  "order, overlap and empty text",
+			cues:  []Cue{{Start: ms(1000), End: ms(3000), Text: "a"}, {Start: ms(2000), End: ms(4000), Text: " "}, {Start: ms(1500), End: ms(5000), Text: "c"}},
+			codes: []string{"warning:empty_cue", "warning:overlap", "error:out_of_order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(Validate(tt.cues, tt.runtime)); !reflect.DeepEqual(got, tt.codes) {
+				t.Errorf("problems = %v, want %v", got, tt.codes)
+			}
+			if HasErrors(Validate(tt.cues, tt.runtime)) != containsError(tt.codes) {
+				t.Errorf("HasErrors disagrees with %v", tt.codes)
+			}
+		})
+	}
+}
+
+func containsError(codes []string) bool {
+	for _, code := range codes {
+		if strings.HasPrefix(code, SeverityError+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEncode(t *testing.T) {
+	cues := []Cue{
+		{ID: "intro", Start: ms(1500), End: time.Hour + ms(2250), Settings: "align:start", Text: "<v Leo>Hello</v> <c.yellow>there</c>"},
+		{Start: ms(3000), End: ms(4000), Text: "Bye\n<i>now</i>"},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "SRT drops WebVTT markup and settings",
+			format: FormatSRT,
+			want:   "1\n00:00:01,500 --> 01:00:02,250\nHello there\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n<i>now</i>\n\n",
+		},
+		{
+			name:   "WebVTT",
+			format: FormatVTT,
+			want:   "WEBVTT\n\nintro\n00:00:01.500 --> 01:00:02.250 align:start\n<v Leo>Hello</v> <c.yellow>there</c>\n\n00:00:03.000 --> 00:00:04.000\nBye\n<i>now</i>\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(Encode(cues, tt.format))
+			if got != tt.want {
+				t.Errorf("Encode = %q, want %q", got, tt.want)
+			}
+
+			// What was written reads back the same, less what the format can't carry
+			parsed, problems := Parse([]byte(got), tt.format)
+			if HasErrors(problems) || len(parsed) != len(cues) {
+				t.Fatalf("re-parse = %+v, %v", parsed, problems)
+			}
+			for i := range parsed {
+				if parsed[i].Start != cues[i].Start || parsed[i].End != cues[i].End {
+					t.Errorf("cue %d timing = %s-%s, want %s-%s", i, parsed[i].Start, parsed[i].End, cues[i].Start, cues[i].End)
+				}
+			}
+		})
+	}
+}
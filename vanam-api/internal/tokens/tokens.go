@@ -0,0 +1,151 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/prabalesh/vanam/vanam-api/pkg/redis"
+)
+
+// Purpose scopes a token so one issued for email verification can't reset a password
+type Purpose string
+
+const (
+	PasswordReset     Purpose = "password_reset"
+	EmailVerification Purpose = "email_verification"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims is what a token stands for, kept in Redis until it is used or expires
+type Claims struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+var secret []byte
+
+// Init sets the HMAC key. Without one a random key is used, so tokens
+// don't survive a restart.
+func Init(key string) {
+	if key != "" {
+		secret = []byte(key)
+		return
+	}
+
+	secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal("Failed to generate token secret:", err)
+	}
+	log.Println("⚠️  TOKEN_SECRET not set, using an ephemeral key")
+}
+
+func tokenKey(purpose Purpose, id string) string {
+	return fmt.Sprintf("token:%s:%s", purpose, id)
+}
+
+// userTokenKey points at the user's latest token, so issuing a new one voids the old
+func userTokenKey(purpose Purpose, userID uint) string {
+	return fmt.Sprintf("token:%s:user:%d", purpose, userID)
+}
+
+// Issue creates a signed, single use token for a user
+func Issue(purpose Purpose, userID uint, email string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(raw)
+
+	claims := Claims{UserID: userID, Email: email, ExpiresAt: time.Now().Add(ttl)}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	previous, _ := redis.Client.Get(redis.Ctx, userTokenKey(purpose, userID)).Result()
+
+	pipe := redis.Client.TxPipeline()
+	if previous != "" {
+		pipe.Del(redis.Ctx, tokenKey(purpose, previous))
+	}
+	pipe.Set(redis.Ctx, tokenKey(purpose, id), data, ttl)
+	pipe.Set(redis.Ctx, userTokenKey(purpose, userID), id, ttl)
+	if _, err := pipe.Exec(redis.Ctx); err != nil {
+		return "", err
+	}
+
+	return id + "." + sign(purpose, id), nil
+}
+
+// Consume checks a token's signature and redeems it. A token works exactly once.
+func Consume(purpose Purpose, token string) (*Claims, error) {
+	claims, err := lookup(purpose, token, true)
+	if err != nil {
+		return nil, err
+	}
+
+	redis.Client.Del(redis.Ctx, userTokenKey(purpose, claims.UserID))
+	return claims, nil
+}
+
+// Peek checks a token without redeeming it, so a request can be validated before
+// the token is spent
+func Peek(purpose Purpose, token string) (*Claims, error) {
+	return lookup(purpose, token, false)
+}
+
+func lookup(purpose Purpose, token string, redeem bool) (*Claims, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(sign(purpose, id))) {
+		return nil, ErrInvalidToken
+	}
+
+	var data string
+	var err error
+	if redeem {
+		data, err = redis.Client.GetDel(redis.Ctx, tokenKey(purpose, id)).Result()
+	} else {
+		data, err = redis.Client.Get(redis.Ctx, tokenKey(purpose, id)).Result()
+	}
+	if err == goredis.Nil {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := json.Unmarshal([]byte(data), &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// Revoke voids a user's outstanding token
+func Revoke(purpose Purpose, userID uint) {
+	if id, err := redis.Client.Get(redis.Ctx, userTokenKey(purpose, userID)).Result(); err == nil {
+		redis.Client.Del(redis.Ctx, tokenKey(purpose, id), userTokenKey(purpose, userID))
+	}
+}
+
+func sign(purpose Purpose, id string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(string(purpose) + ":" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
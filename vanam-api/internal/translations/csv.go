@@ -0,0 +1,94 @@
+package translations
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var csvHeader = []string{"key", "language", "source", "target", "status"}
+
+const (
+	statusMissing    = "missing"
+	statusTranslated = "translated"
+)
+
+// WriteCSV writes units as a spreadsheet with one row per field
+func WriteCSV(w io.Writer, units []Unit) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, unit := range units {
+		status := statusTranslated
+		if unit.Missing {
+			status = statusMissing
+		}
+		if err := writer.Write([]string{unit.Key.String(), unit.Language, unit.Source, unit.Target, status}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads a spreadsheet written by WriteCSV. Columns are found by header name, so
+// translators may reorder them or add their own. Rows that can't be understood are
+// reported as issues, and an error is returned only if the file isn't CSV at all.
+func ReadCSV(r io.Reader) ([]Unit, []Issue, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"key", "language", "target"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV is missing the %q column", required)
+		}
+	}
+
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var units []Unit
+	var issues []Issue
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		key, err := ParseKey(column(record, "key"))
+		if err != nil {
+			issues = append(issues, Issue{Line: line, Key: column(record, "key"), Message: err.Error()})
+			continue
+		}
+
+		units = append(units, Unit{
+			Key:      key,
+			Language: strings.TrimSpace(column(record, "language")),
+			Source:   column(record, "source"),
+			Target:   column(record, "target"),
+			Line:     line,
+		})
+	}
+
+	return units, issues, nil
+}
@@ -0,0 +1,76 @@
+package translations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Translatable catalog fields. Keys look like "movie:12:title" or "genre:3:name".
+const (
+	KindMovie = "movie"
+	KindGenre = "genre"
+
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldName        = "name"
+)
+
+var kindFields = map[string][]string{
+	KindMovie: {FieldTitle, FieldDescription},
+	KindGenre: {FieldName},
+}
+
+// Key identifies one translatable field of one catalog entry
+type Key struct {
+	Kind  string
+	ID    uint
+	Field string
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s:%d:%s", k.Kind, k.ID, k.Field)
+}
+
+// ParseKey reads a key written by Key.String
+func ParseKey(s string) (Key, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return Key{}, fmt.Errorf("malformed key %q", s)
+	}
+
+	fields, ok := kindFields[parts[0]]
+	if !ok {
+		return Key{}, fmt.Errorf("unknown kind %q", parts[0])
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil || id == 0 {
+		return Key{}, fmt.Errorf("invalid ID %q", parts[1])
+	}
+
+	for _, field := range fields {
+		if field == parts[2] {
+			return Key{Kind: parts[0], ID: uint(id), Field: field}, nil
+		}
+	}
+	return Key{}, fmt.Errorf("unknown %s field %q", parts[0], parts[2])
+}
+
+// Unit is one field's source text and its translation into Language
+type Unit struct {
+	Key      Key
+	Language string
+	Source   string
+	Target   string
+	Missing  bool // Exported without a translation
+	Line     int  // Row or unit number in an imported file
+}
+
+// Issue is a problem with one entry of an imported file
+type Issue struct {
+	Line     int    `json:"line"`
+	Key      string `json:"key,omitempty"`
+	Language string `json:"language,omitempty"`
+	Message  string `json:"message"`
+}
@@ -0,0 +1,109 @@
+package translations
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// XLIFF 1.2, which most translation tools read and write
+
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Version string      `xml:"version,attr"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr"`
+	Datatype       string      `xml:"datatype,attr"`
+	Units          []xliffUnit `xml:"body>trans-unit"`
+}
+
+type xliffUnit struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source"`
+	Target xliffTarget `xml:"target"`
+	Note   string      `xml:"note,omitempty"`
+}
+
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+var fieldNotes = map[string]string{
+	FieldTitle:       "Movie title",
+	FieldDescription: "Movie description",
+	FieldName:        "Genre name",
+}
+
+// WriteXLIFF writes units for a single target language
+func WriteXLIFF(w io.Writer, sourceLanguage, targetLanguage string, units []Unit) error {
+	file := xliffFile{
+		Original:       "vanam-catalog",
+		SourceLanguage: sourceLanguage,
+		TargetLanguage: targetLanguage,
+		Datatype:       "plaintext",
+	}
+	for _, unit := range units {
+		state := "translated"
+		if unit.Missing {
+			state = "needs-translation"
+		}
+		file.Units = append(file.Units, xliffUnit{
+			ID:     unit.Key.String(),
+			Source: unit.Source,
+			Target: xliffTarget{State: state, Text: unit.Target},
+			Note:   fieldNotes[unit.Key.Field],
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(xliffDocument{Version: "1.2", Xmlns: xliffNamespace, Files: []xliffFile{file}})
+}
+
+// ReadXLIFF reads every file in an XLIFF 1.2 document, taking each unit's language from
+// its file's target-language
+func ReadXLIFF(r io.Reader) ([]Unit, []Issue, error) {
+	var document xliffDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse XLIFF: %w", err)
+	}
+	if document.Version != "" && document.Version != "1.2" {
+		return nil, nil, fmt.Errorf("unsupported XLIFF version %q", document.Version)
+	}
+
+	var units []Unit
+	var issues []Issue
+	position := 0
+	for _, file := range document.Files {
+		for _, unit := range file.Units {
+			position++
+			key, err := ParseKey(unit.ID)
+			if err != nil {
+				issues = append(issues, Issue{Line: position, Key: unit.ID, Language: file.TargetLanguage, Message: err.Error()})
+				continue
+			}
+
+			units = append(units, Unit{
+				Key:      key,
+				Language: file.TargetLanguage,
+				Source:   unit.Source,
+				Target:   unit.Target.Text,
+				Line:     position,
+			})
+		}
+	}
+
+	return units, issues, nil
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const ticketCodePrefix = "VT1"
+
+var (
+	ErrInvalidTicketCode = errors.New("invalid ticket code")
+	ErrExpiredTicketCode = errors.New("ticket code has expired")
+)
+
+// TicketClaims is the payload carried inside a signed ticket code
+type TicketClaims struct {
+	TicketID    uint   `json:"tid"`
+	BookingID   uint   `json:"bid"`
+	ScreeningID uint   `json:"sid"`
+	SeatID      uint   `json:"seat"`
+	SeatNumber  string `json:"sn"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+var ticketSigningKey ed25519.PrivateKey
+
+// InitTicketSigner loads the ed25519 signing key from a hex encoded 32 byte seed.
+// An empty seed generates a throwaway key so development setups keep working.
+func InitTicketSigner(seedHex string) error {
+	if seedHex == "" {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		ticketSigningKey = key
+		log.Println("⚠️  TICKET_SIGNING_KEY not set, using an ephemeral key (tickets won't survive a restart)")
+		return nil
+	}
+
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("ticket signing key must be %d hex encoded bytes", ed25519.SeedSize)
+	}
+	ticketSigningKey = ed25519.NewKeyFromSeed(seed)
+	return nil
+}
+
+// TicketPublicKey returns the key scanners use to verify codes offline
+func TicketPublicKey() ed25519.PublicKey {
+	return ticketSigningKey.Public().(ed25519.PublicKey)
+}
+
+// SignTicketCode produces a compact "VT1.<payload>.<signature>" string suitable for a QR code
+func SignTicketCode(claims TicketClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signed := ticketCodePrefix + "." + encodedPayload
+	signature := ed25519.Sign(ticketSigningKey, []byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyTicketCode checks the signature and expiry of a ticket code and returns its claims
+func VerifyTicketCode(code string) (*TicketClaims, error) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 3 || parts[0] != ticketCodePrefix {
+		return nil, ErrInvalidTicketCode
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+
+	if !ed25519.Verify(TicketPublicKey(), []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidTicketCode
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+
+	var claims TicketClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+
+	if time.Now().Unix() > claims.ExpiresAt {
+		return &claims, ErrExpiredTicketCode
+	}
+
+	return &claims, nil
+}
+
+// GenerateBookingRef returns a short human readable booking reference
+func GenerateBookingRef() string {
+	bytes := make([]byte, 5)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("VNM%d", time.Now().UnixNano())
+	}
+	return "VNM" + strings.ToUpper(hex.EncodeToString(bytes))
+}
@@ -60,3 +60,18 @@ func ErrorResponse(message string) map[string]interface{} {
 		"message": message,
 	}
 }
+
+// FieldError describes why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func ValidationErrorResponse(message string, errors []FieldError) map[string]interface{} {
+	return map[string]interface{}{
+		"success": false,
+		"message": message,
+		"errors":  errors,
+	}
+}